@@ -0,0 +1,74 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/redis/go-redis/v9"
+	"github.com/segmentio/kafka-go"
+	"gorm.io/gorm"
+)
+
+func Postgres(db *gorm.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}
+}
+
+func Redis(rdb *redis.Client) CheckFunc {
+	return func(ctx context.Context) error {
+		return rdb.Ping(ctx).Err()
+	}
+}
+
+func Milvus(client *milvusclient.Client) CheckFunc {
+	return func(ctx context.Context) error {
+		_, err := client.GetServerVersion(ctx, milvusclient.NewGetServerVersionOption())
+		return err
+	}
+}
+
+// Kafka checks that the broker accepts connections and answers a metadata
+// request.
+func Kafka(addr string) CheckFunc {
+	return func(ctx context.Context) error {
+		conn, err := kafka.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetDeadline(deadline)
+		}
+		_, err = conn.Brokers()
+		return err
+	}
+}
+
+// HTTP treats any answer below 500 as healthy. MCP endpoints, for example,
+// reject a plain GET but are clearly up when they do.
+func HTTP(url string) CheckFunc {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
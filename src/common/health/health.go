@@ -0,0 +1,230 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+type CheckFunc func(ctx context.Context) error
+
+type CheckResult struct {
+	Name      string        `json:"name"`
+	Status    string        `json:"status"`
+	Error     string        `json:"error,omitempty"`
+	Latency   time.Duration `json:"latency"`
+	CheckedAt time.Time     `json:"checked_at"`
+}
+
+type Report struct {
+	Status string         `json:"status"`
+	Checks []*CheckResult `json:"checks"`
+}
+
+type namedCheck struct {
+	name  string
+	check CheckFunc
+}
+
+// Checker runs dependency checks in the background and publishes the
+// aggregated result through grpc.health.v1, so Consul, Kubernetes probes and
+// the gateway all see the same readiness signal.
+type Checker struct {
+	mu       sync.RWMutex
+	checks   []namedCheck
+	results  map[string]*CheckResult
+	stopped  bool
+	server   *grpchealth.Server
+	services []string
+	interval time.Duration
+	timeout  time.Duration
+}
+
+func NewChecker() *Checker {
+	interval := viper.GetDuration("health.interval")
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	timeout := viper.GetDuration("health.timeout")
+	if timeout <= 0 {
+		timeout = 3 * time.Second
+	}
+
+	return &Checker{
+		results:  make(map[string]*CheckResult),
+		server:   grpchealth.NewServer(),
+		interval: interval,
+		timeout:  timeout,
+	}
+}
+
+func (c *Checker) Add(name string, check CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// Register serves grpc.health.v1 on s. Call it after every other service has
+// been registered so each of them gets a per-service status as well.
+func (c *Checker) Register(s *grpc.Server) {
+	healthpb.RegisterHealthServer(s, c.server)
+
+	for name := range s.GetServiceInfo() {
+		if name != healthpb.Health_ServiceDesc.ServiceName {
+			c.services = append(c.services, name)
+		}
+	}
+	c.publish(healthpb.HealthCheckResponse_NOT_SERVING)
+}
+
+// Start runs the checks once before returning and then keeps refreshing them
+// until ctx is done.
+func (c *Checker) Start(ctx context.Context) {
+	c.run(ctx)
+
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.run(ctx)
+			}
+		}
+	}()
+}
+
+// Shutdown reports NOT_SERVING from now on, regardless of the checks.
+func (c *Checker) Shutdown() {
+	c.mu.Lock()
+	c.stopped = true
+	c.mu.Unlock()
+
+	c.server.Shutdown()
+}
+
+func (c *Checker) run(ctx context.Context) {
+	c.mu.RLock()
+	checks := c.checks
+	c.mu.RUnlock()
+
+	results := make([]*CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, nc := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.runOne(ctx, nc)
+		}()
+	}
+	wg.Wait()
+
+	c.mu.Lock()
+	for _, r := range results {
+		prev, seen := c.results[r.Name]
+		if r.Status == StatusDown && (!seen || prev.Status == StatusUp) {
+			zap.L().Warn("Dependency check failed", zap.String("check", r.Name), zap.String("error", r.Error))
+		} else if r.Status == StatusUp && seen && prev.Status == StatusDown {
+			zap.L().Info("Dependency check recovered", zap.String("check", r.Name))
+		}
+		c.results[r.Name] = r
+	}
+	stopped := c.stopped
+	c.mu.Unlock()
+
+	if stopped {
+		return
+	}
+	if c.Err() != nil {
+		c.publish(healthpb.HealthCheckResponse_NOT_SERVING)
+	} else {
+		c.publish(healthpb.HealthCheckResponse_SERVING)
+	}
+}
+
+func (c *Checker) runOne(ctx context.Context, nc namedCheck) *CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := nc.check(ctx)
+
+	result := &CheckResult{
+		Name:      nc.name,
+		Status:    StatusUp,
+		Latency:   time.Since(start),
+		CheckedAt: start,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func (c *Checker) publish(status healthpb.HealthCheckResponse_ServingStatus) {
+	c.server.SetServingStatus("", status)
+	for _, name := range c.services {
+		c.server.SetServingStatus(name, status)
+	}
+}
+
+// Err returns nil when every check passed on its last run, and otherwise an
+// error naming the failing dependencies.
+func (c *Checker) Err() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.stopped {
+		return errors.New("shutting down")
+	}
+
+	var errs []error
+	for _, nc := range c.checks {
+		r, ok := c.results[nc.name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: not checked yet", nc.name))
+		} else if r.Status == StatusDown {
+			errs = append(errs, fmt.Errorf("%s: %s", r.Name, r.Error))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Checker) Report() *Report {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	report := &Report{Status: StatusUp, Checks: make([]*CheckResult, 0, len(c.checks))}
+	for _, nc := range c.checks {
+		r, ok := c.results[nc.name]
+		if !ok {
+			r = &CheckResult{Name: nc.name, Status: StatusDown, Error: "not checked yet"}
+		}
+		if r.Status == StatusDown {
+			report.Status = StatusDown
+		}
+		report.Checks = append(report.Checks, r)
+	}
+	if c.stopped {
+		report.Status = StatusDown
+	}
+	return report
+}
@@ -0,0 +1,224 @@
+package metering
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	callbacksutil "github.com/cloudwego/eino/utils/callbacks"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	keyPrefix = "usage"
+
+	PeriodDay   = "day"
+	PeriodMonth = "month"
+
+	dayLayout   = "20060102"
+	monthLayout = "200601"
+
+	dayTTL   = 35 * 24 * time.Hour
+	monthTTL = 400 * 24 * time.Hour
+
+	fieldPrompt     = "prompt_tokens"
+	fieldCompletion = "completion_tokens"
+	fieldTotal      = "total_tokens"
+	fieldNodePrefix = "node:"
+
+	unknownNode = "unknown"
+)
+
+type Usage struct {
+	PromptTokens     int64
+	CompletionTokens int64
+	TotalTokens      int64
+}
+
+type Report struct {
+	Usage
+	Nodes map[string]int64
+}
+
+type Recorder interface {
+	Record(ctx context.Context, userID uint, node string, usage *Usage) error
+}
+
+func DailyKey(userID uint, t time.Time) string {
+	return fmt.Sprintf("%s:%d:%s:%s", keyPrefix, userID, PeriodDay, t.Format(dayLayout))
+}
+
+func MonthlyKey(userID uint, t time.Time) string {
+	return fmt.Sprintf("%s:%d:%s:%s", keyPrefix, userID, PeriodMonth, t.Format(monthLayout))
+}
+
+// UserKeyPattern matches every usage counter recorded for a user.
+func UserKeyPattern(userID uint) string {
+	return fmt.Sprintf("%s:%d:*", keyPrefix, userID)
+}
+
+type redisRecorder struct {
+	client *redis.Client
+}
+
+func NewRedisRecorder(client *redis.Client) Recorder {
+	return &redisRecorder{client: client}
+}
+
+func (r *redisRecorder) Record(ctx context.Context, userID uint, node string, usage *Usage) error {
+	if node == "" {
+		node = unknownNode
+	}
+
+	now := time.Now()
+	pipe := r.client.TxPipeline()
+	for key, ttl := range map[string]time.Duration{
+		DailyKey(userID, now):   dayTTL,
+		MonthlyKey(userID, now): monthTTL,
+	} {
+		pipe.HIncrBy(ctx, key, fieldPrompt, usage.PromptTokens)
+		pipe.HIncrBy(ctx, key, fieldCompletion, usage.CompletionTokens)
+		pipe.HIncrBy(ctx, key, fieldTotal, usage.TotalTokens)
+		pipe.HIncrBy(ctx, key, fieldNodePrefix+node, usage.TotalTokens)
+		pipe.Expire(ctx, key, ttl)
+	}
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Tally adds up the usage recorded through it so a caller can report what a
+// single request consumed. Every record is passed on to next.
+type Tally struct {
+	next Recorder
+
+	mu      sync.Mutex
+	usage   Usage
+	pending sync.WaitGroup
+}
+
+func NewTally(next Recorder) *Tally {
+	return &Tally{next: next}
+}
+
+func (t *Tally) Record(ctx context.Context, userID uint, node string, usage *Usage) error {
+	t.mu.Lock()
+	t.usage.PromptTokens += usage.PromptTokens
+	t.usage.CompletionTokens += usage.CompletionTokens
+	t.usage.TotalTokens += usage.TotalTokens
+	t.mu.Unlock()
+
+	return t.next.Record(ctx, userID, node, usage)
+}
+
+// Usage returns the total recorded so far, first waiting until ctx is done
+// for usage still being read from streamed model output.
+func (t *Tally) Usage(ctx context.Context) Usage {
+	done := make(chan struct{})
+	go func() {
+		t.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.usage
+}
+
+func Read(ctx context.Context, client *redis.Client, key string) (*Report, error) {
+	fields, err := client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Nodes: make(map[string]int64)}
+	for field, value := range fields {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		switch field {
+		case fieldPrompt:
+			report.PromptTokens = n
+		case fieldCompletion:
+			report.CompletionTokens = n
+		case fieldTotal:
+			report.TotalTokens = n
+		default:
+			if node, ok := strings.CutPrefix(field, fieldNodePrefix); ok {
+				report.Nodes[node] = n
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// NewHandler returns a chat model callback handler that attributes the token
+// usage reported by each model node to userID. Nodes are identified by the
+// name given through compose.WithNodeName.
+func NewHandler(recorder Recorder, userID uint) callbacks.Handler {
+	record := func(ctx context.Context, runInfo *callbacks.RunInfo, usage *model.TokenUsage) {
+		if usage == nil || usage.TotalTokens == 0 {
+			return
+		}
+		node := ""
+		if runInfo != nil {
+			node = runInfo.Name
+		}
+		if err := recorder.Record(context.WithoutCancel(ctx), userID, node, &Usage{
+			PromptTokens:     int64(usage.PromptTokens),
+			CompletionTokens: int64(usage.CompletionTokens),
+			TotalTokens:      int64(usage.TotalTokens),
+		}); err != nil {
+			zap.L().Error("failed to record token usage", zap.Uint("userID", userID), zap.String("node", node), zap.Error(err))
+		}
+	}
+
+	return callbacksutil.NewHandlerHelper().ChatModel(&callbacksutil.ModelCallbackHandler{
+		OnEnd: func(ctx context.Context, runInfo *callbacks.RunInfo, output *model.CallbackOutput) context.Context {
+			if output != nil {
+				record(ctx, runInfo, output.TokenUsage)
+			}
+			return ctx
+		},
+		OnEndWithStreamOutput: func(ctx context.Context, runInfo *callbacks.RunInfo, output *schema.StreamReader[*model.CallbackOutput]) context.Context {
+			done := func() {}
+			if tally, ok := recorder.(*Tally); ok {
+				tally.pending.Add(1)
+				done = tally.pending.Done
+			}
+
+			go func() {
+				defer done()
+				defer output.Close()
+
+				// Providers report usage on the final chunk, so keep the last one seen.
+				var usage *model.TokenUsage
+				for {
+					chunk, err := output.Recv()
+					if err != nil {
+						break
+					}
+					if chunk != nil && chunk.TokenUsage != nil {
+						usage = chunk.TokenUsage
+					}
+				}
+				record(ctx, runInfo, usage)
+			}()
+			return ctx
+		},
+	}).Handler()
+}
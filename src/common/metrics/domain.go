@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	ToolOutcomeSuccess     = "success"
+	ToolOutcomeError       = "error"
+	ToolOutcomeNotFound    = "not_found"
+	ToolOutcomeUnsupported = "unsupported"
+
+	TransitionSTMToMTM = "stm_to_mtm"
+	TransitionMTMToLTM = "mtm_to_ltm"
+)
+
+var (
+	ChatFirstChunkSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "doria_chat_stream_first_chunk_seconds",
+		Help:    "Time from a chat stream request to its first content chunk.",
+		Buckets: []float64{0.1, 0.25, 0.5, 1, 2, 4, 8, 16, 32},
+	})
+
+	GuidelineLoopIterations = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "doria_guideline_loop_iterations",
+		Help:    "Guideline loop iterations the mate agent ran before answering.",
+		Buckets: []float64{1, 2, 3, 4, 5, 6, 8, 10},
+	})
+
+	ToolCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "doria_tool_calls_total",
+		Help: "Agent tool calls by tool and outcome.",
+	}, []string{"tool", "outcome"})
+
+	MemoryTransitionItems = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "doria_memory_transition_items",
+		Help:    "Items moved per memory transition run.",
+		Buckets: []float64{0, 1, 2, 5, 10, 20, 50, 100},
+	}, []string{"transition"})
+
+	KafkaConsumerLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "doria_kafka_consumer_lag",
+		Help: "Messages behind the partition high watermark, as of the last message read.",
+	}, []string{"topic", "partition"})
+
+	TTSSynthesisSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "doria_tts_synthesis_seconds",
+		Help:    "Speech synthesis latency per sentence.",
+		Buckets: []float64{0.1, 0.25, 0.5, 1, 2, 4, 8},
+	})
+
+	WebRTCSessions = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "doria_webrtc_sessions",
+		Help: "WebRTC sessions currently connected.",
+	})
+
+	LoginLockouts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "doria_login_lockouts_total",
+		Help: "Temporary login lockouts by what was locked, phone or ip.",
+	}, []string{"scope"})
+
+	AccountDeletionSteps = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "doria_account_deletion_steps_total",
+		Help: "Account deletion step runs by step and outcome.",
+	}, []string{"step", "outcome"})
+)
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var grpcHandlingSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "doria_grpc_server_handling_seconds",
+	Help:    "gRPC server handling latency by method and status code.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"method", "code"})
+
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observeGRPC(info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observeGRPC(info.FullMethod, start, err)
+		return err
+	}
+}
+
+func observeGRPC(method string, start time.Time, err error) {
+	grpcHandlingSeconds.WithLabelValues(method, status.Code(err).String()).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var httpRequestSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "doria_http_request_duration_seconds",
+	Help:    "HTTP request latency by method, route template and status code.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"method", "route", "status"})
+
+// Gin records every request under its route template rather than the raw
+// path, so path parameters do not blow up the label cardinality.
+func Gin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		httpRequestSeconds.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// Serve exposes /metrics on metrics.addr in the background. Services that
+// leave the address empty are not scraped.
+func Serve(appName string) {
+	addr := viper.GetString("metrics.addr")
+	if addr == "" {
+		zap.L().Info("Metrics address is empty, the metrics server would not run.",
+			zap.String("appName", appName))
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			zap.L().Error("Error while run metrics server.",
+				zap.String("appName", appName),
+				zap.Error(err))
+		}
+	}()
+}
@@ -0,0 +1,50 @@
+package rbac
+
+import "slices"
+
+const (
+	RoleUser    = "user"
+	RoleSupport = "support"
+	RoleAdmin   = "admin"
+)
+
+const (
+	PermUsersRead       = "users:read"
+	PermUsersWrite      = "users:write"
+	PermMemoryRead      = "memory:read"
+	PermMemoryReprocess = "memory:reprocess"
+	PermAuditRead       = "audit:read"
+	PermBreakersManage  = "breakers:manage"
+)
+
+// rolePermissions is the single source of truth for what each role may do.
+// Plain users have no admin permissions at all.
+var rolePermissions = map[string][]string{
+	RoleUser: {},
+	RoleSupport: {
+		PermUsersRead,
+		PermMemoryRead,
+		PermAuditRead,
+	},
+	RoleAdmin: {
+		PermUsersRead,
+		PermUsersWrite,
+		PermMemoryRead,
+		PermMemoryReprocess,
+		PermAuditRead,
+		PermBreakersManage,
+	},
+}
+
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+func Permissions(role string) []string {
+	return slices.Clone(rolePermissions[role])
+}
+
+func Has(role, permission string) bool {
+	return slices.Contains(rolePermissions[role], permission)
+}
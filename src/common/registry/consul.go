@@ -46,12 +46,22 @@ func (c *ConsulClient) RegisterService(serviceName string) (string, error) {
 	return serviceID, c.client.Agent().ServiceRegister(registration)
 }
 
-func (c *ConsulClient) SetTTLHealthCheck() {
+// SetTTLHealthCheck reports the result of check to Consul every 5s. A
+// failing check is reported as a warning rather than critical: it takes the
+// instance out of the healthy set without triggering the critical-service
+// deregistration.
+func (c *ConsulClient) SetTTLHealthCheck(check func() error) {
 	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
 
 	for range ticker.C {
-		if err := c.passTTL(c.serviceID, "Service is healthy"); err != nil {
+		var err error
+		if checkErr := check(); checkErr != nil {
+			err = c.warnTTL(c.serviceID, checkErr.Error())
+		} else {
+			err = c.passTTL(c.serviceID, "Service is healthy")
+		}
+		if err != nil {
 			zap.L().Error(err.Error())
 		}
 	}
@@ -67,6 +77,10 @@ func (c *ConsulClient) passTTL(serviceID, note string) error {
 	return c.updateTTL(serviceID, "pass", note)
 }
 
+func (c *ConsulClient) warnTTL(serviceID, note string) error {
+	return c.updateTTL(serviceID, "warn", note)
+}
+
 func (c *ConsulClient) DeregisterService(serviceID string) error {
 	return c.client.Agent().ServiceDeregister(serviceID)
 }
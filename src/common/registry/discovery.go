@@ -49,7 +49,7 @@ func NewConsulDiscovery() *ConsulDiscovery {
 
 func (cd *ConsulDiscovery) CreateGrpcConnection(ctx context.Context, serviceName string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
 	consulServiceName := cd.getConsulServiceName(serviceName)
-	address := fmt.Sprintf("consul://%s/%s?wait=30s", cd.consulAddr, consulServiceName)
+	address := fmt.Sprintf("consul://%s/%s?wait=30s&healthy=true", cd.consulAddr, consulServiceName)
 
 	zap.L().Info("Connecting to service via Consul",
 		zap.String("service", serviceName),
@@ -7,7 +7,7 @@ import (
 
 type ServiceRegistrar interface {
 	RegisterService(serviceName string) (string, error)
-	SetTTLHealthCheck()
+	SetTTLHealthCheck(check func() error)
 	DeregisterService(serviceID string) error
 }
 
@@ -39,8 +39,8 @@ func (rm *RegistrationManager) RegisterService(serviceName string) (string, erro
 	return rm.registrar.RegisterService(serviceName)
 }
 
-func (rm *RegistrationManager) SetTTLHealthCheck() {
-	rm.registrar.SetTTLHealthCheck()
+func (rm *RegistrationManager) SetTTLHealthCheck(check func() error) {
+	rm.registrar.SetTTLHealthCheck(check)
 }
 
 func (rm *RegistrationManager) DeregisterService(serviceID string) error {
@@ -55,7 +55,7 @@ func (nr *NoOpRegistrar) RegisterService(serviceName string) (string, error) {
 	return "k8s-dns-mode", nil
 }
 
-func (nr *NoOpRegistrar) SetTTLHealthCheck() {
+func (nr *NoOpRegistrar) SetTTLHealthCheck(check func() error) {
 	zap.L().Info("Health check skipped (Kubernetes DNS enabled)")
 }
 
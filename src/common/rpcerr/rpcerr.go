@@ -0,0 +1,255 @@
+package rpcerr
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Domain is the google.rpc.ErrorInfo domain every Doria service reports.
+const Domain = "doria"
+
+const (
+	ReasonInternal      = "INTERNAL"
+	ReasonValidation    = "VALIDATION_FAILED"
+	ReasonNotFound      = "NOT_FOUND"
+	ReasonUserExists    = "USER_EXISTS"
+	ReasonInvalidCode   = "INVALID_VERIFICATION_CODE"
+	ReasonUserNotFound  = "USER_NOT_FOUND"
+	ReasonWrongPassword = "WRONG_PASSWORD"
+	ReasonQuotaExceeded = "QUOTA_EXCEEDED"
+	ReasonUpstreamLLM   = "UPSTREAM_LLM_FAILED"
+	ReasonDegraded      = "DEGRADED"
+	ReasonCanceled      = "CANCELED"
+
+	ReasonAccountDisabled  = "ACCOUNT_DISABLED"
+	ReasonPermissionDenied = "PERMISSION_DENIED"
+	ReasonRateLimited      = "RATE_LIMITED"
+	ReasonAccountLocked    = "ACCOUNT_LOCKED"
+	// ReasonInvalidCredentials covers both an unknown login and a wrong
+	// password, so a failed login doesn't reveal which accounts exist.
+	ReasonInvalidCredentials = "INVALID_CREDENTIALS"
+)
+
+var reasonCodes = map[string]codes.Code{
+	ReasonInternal:      codes.Internal,
+	ReasonValidation:    codes.InvalidArgument,
+	ReasonNotFound:      codes.NotFound,
+	ReasonUserExists:    codes.AlreadyExists,
+	ReasonInvalidCode:   codes.InvalidArgument,
+	ReasonUserNotFound:  codes.NotFound,
+	ReasonWrongPassword: codes.Unauthenticated,
+	ReasonQuotaExceeded: codes.ResourceExhausted,
+	ReasonUpstreamLLM:   codes.Unavailable,
+	ReasonDegraded:      codes.Unavailable,
+	ReasonCanceled:      codes.Canceled,
+
+	ReasonAccountDisabled:  codes.PermissionDenied,
+	ReasonPermissionDenied: codes.PermissionDenied,
+	ReasonRateLimited:      codes.ResourceExhausted,
+	ReasonAccountLocked:    codes.ResourceExhausted,
+
+	ReasonInvalidCredentials: codes.Unauthenticated,
+}
+
+// codeReasons classifies status errors that arrive without an ErrorInfo,
+// e.g. ones produced by grpc-go itself.
+var codeReasons = map[codes.Code]string{
+	codes.InvalidArgument:   ReasonValidation,
+	codes.NotFound:          ReasonNotFound,
+	codes.AlreadyExists:     ReasonValidation,
+	codes.ResourceExhausted: ReasonQuotaExceeded,
+	codes.Unavailable:       ReasonDegraded,
+	codes.DeadlineExceeded:  ReasonDegraded,
+	codes.Canceled:          ReasonCanceled,
+	codes.PermissionDenied:  ReasonPermissionDenied,
+}
+
+// clientReasons are outcomes caused by the request rather than by the
+// service; they must not count against circuit breakers.
+var clientReasons = map[string]bool{
+	ReasonValidation:    true,
+	ReasonNotFound:      true,
+	ReasonUserExists:    true,
+	ReasonInvalidCode:   true,
+	ReasonUserNotFound:  true,
+	ReasonWrongPassword: true,
+	ReasonQuotaExceeded: true,
+	ReasonCanceled:      true,
+
+	ReasonAccountDisabled:  true,
+	ReasonPermissionDenied: true,
+	ReasonRateLimited:      true,
+	ReasonAccountLocked:    true,
+
+	ReasonInvalidCredentials: true,
+}
+
+// Error is a business error with a stable reason. It converts itself into a
+// gRPC status carrying a google.rpc.ErrorInfo, so handlers can return it as
+// is, directly or wrapped.
+type Error struct {
+	Reason  string
+	Message string
+	// RetryAfter, when set, is sent as a google.rpc.RetryInfo detail.
+	RetryAfter time.Duration
+	cause      error
+}
+
+func New(reason, message string) *Error {
+	return &Error{Reason: reason, Message: message}
+}
+
+func Wrap(reason string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Reason: reason, Message: err.Error(), cause: err}
+}
+
+// WithRetryAfter returns a copy of e telling the caller when to try again.
+func (e *Error) WithRetryAfter(d time.Duration) *Error {
+	withRetry := *e
+	withRetry.RetryAfter = d
+	return &withRetry
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+func (e *Error) GRPCStatus() *status.Status {
+	code, ok := reasonCodes[e.Reason]
+	if !ok {
+		code = codes.Unknown
+	}
+	st := withInfo(status.New(code, e.Message), e.Reason)
+	if e.RetryAfter > 0 {
+		if withRetry, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(e.RetryAfter)}); err == nil {
+			st = withRetry
+		}
+	}
+	return st
+}
+
+// FromError turns any error into a status error carrying an ErrorInfo.
+func FromError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return e.GRPCStatus().Err()
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return withInfo(status.New(codes.Canceled, err.Error()), ReasonCanceled).Err()
+	case errors.Is(err, context.DeadlineExceeded):
+		return withInfo(status.New(codes.DeadlineExceeded, err.Error()), ReasonDegraded).Err()
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return withInfo(status.New(codes.Internal, err.Error()), ReasonInternal).Err()
+	}
+	if info := errorInfo(st); info != nil {
+		return err
+	}
+	return withInfo(st, reasonForCode(st.Code())).Err()
+}
+
+// ReasonOf returns the ErrorInfo reason carried by err, falling back to one
+// derived from its status code. It returns "" for a nil error.
+func ReasonOf(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Reason
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		if errors.Is(err, context.Canceled) {
+			return ReasonCanceled
+		}
+		return ReasonInternal
+	}
+	if info := errorInfo(st); info != nil {
+		return info.Reason
+	}
+	return reasonForCode(st.Code())
+}
+
+// HasReason reports whether err carries an explicit reason, either as an
+// *Error or as a status with a Doria ErrorInfo, rather than one derived from
+// its status code.
+func HasReason(err error) bool {
+	var e *Error
+	if errors.As(err, &e) {
+		return true
+	}
+
+	st, ok := status.FromError(err)
+	return ok && errorInfo(st) != nil
+}
+
+// RetryAfterOf returns the retry delay carried by err, or 0 if it has none.
+func RetryAfterOf(err error) time.Duration {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.RetryAfter
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return 0
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			return info.GetRetryDelay().AsDuration()
+		}
+	}
+	return 0
+}
+
+func IsClientError(err error) bool {
+	return err != nil && clientReasons[ReasonOf(err)]
+}
+
+func reasonForCode(code codes.Code) string {
+	if reason, ok := codeReasons[code]; ok {
+		return reason
+	}
+	return ReasonInternal
+}
+
+func errorInfo(st *status.Status) *errdetails.ErrorInfo {
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.Domain == Domain {
+			return info
+		}
+	}
+	return nil
+}
+
+func withInfo(st *status.Status, reason string) *status.Status {
+	withDetails, err := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: Domain})
+	if err != nil {
+		return st
+	}
+	return withDetails
+}
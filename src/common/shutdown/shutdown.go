@@ -0,0 +1,123 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+var ErrDraining = errors.New("server is shutting down")
+
+// Coordinator tracks long-lived work such as streams and media pipelines so a
+// deploy can wait for it instead of cutting it off. Once Drain is called no
+// new work is accepted, holders are told through Draining to wind down, and
+// whatever is still running when the grace period ends sees Context canceled.
+type Coordinator struct {
+	mu       sync.Mutex
+	inflight sync.WaitGroup
+	draining bool
+	drainCh  chan struct{}
+	hooks    []func()
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	grace  time.Duration
+}
+
+func NewCoordinator() *Coordinator {
+	grace := viper.GetDuration("shutdown.grace_period")
+	if grace <= 0 {
+		grace = 30 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Coordinator{
+		drainCh: make(chan struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+		grace:   grace,
+	}
+}
+
+// Track registers one unit of in-flight work. The returned func must be
+// called when the work is done. ok is false once draining has started.
+func (c *Coordinator) Track() (done func(), ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.draining {
+		return nil, false
+	}
+	c.inflight.Add(1)
+
+	var once sync.Once
+	return func() { once.Do(c.inflight.Done) }, true
+}
+
+// Draining is closed as soon as Drain is called.
+func (c *Coordinator) Draining() <-chan struct{} {
+	return c.drainCh
+}
+
+func (c *Coordinator) IsDraining() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.draining
+}
+
+// Context is canceled when the grace period is over, or when Drain finishes
+// early because everything completed. Work that must not outlive the process
+// should derive from it instead of context.Background.
+func (c *Coordinator) Context() context.Context {
+	return c.ctx
+}
+
+// RegisterOnDrained adds f to the funcs run after in-flight work has finished
+// or the grace period expired, e.g. to close connections nothing tracks.
+func (c *Coordinator) RegisterOnDrained(f func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.hooks = append(c.hooks, f)
+}
+
+// Drain stops accepting new work and waits for tracked work to finish for up
+// to the grace period or until ctx is done. A non-nil error means some work
+// was still running and has been cut off through Context.
+func (c *Coordinator) Drain(ctx context.Context) error {
+	c.mu.Lock()
+	if c.draining {
+		c.mu.Unlock()
+		return nil
+	}
+	c.draining = true
+	close(c.drainCh)
+	hooks := c.hooks
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, c.grace)
+	defer cancel()
+
+	finished := make(chan struct{})
+	go func() {
+		c.inflight.Wait()
+		close(finished)
+	}()
+
+	var err error
+	select {
+	case <-finished:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	c.cancel()
+
+	for _, hook := range hooks {
+		hook()
+	}
+	return err
+}
@@ -15,4 +15,18 @@ var (
 const (
 	STMPageCachePrefix = "stm_pages"
 	STMPageCacheTTL    = 12 * time.Hour
+
+	ChatStreamBufferPrefix = "chat_stream"
+	ChatStreamBufferTTL    = 5 * time.Minute
+
+	// Per-user gateway state. The user service erases it when an account is
+	// deleted.
+	SessionPrefix      = "session"
+	UserSessionsPrefix = "user_sessions"
+	QuotaPlanPrefix    = "quota_plan"
+	IdempotencyPrefix  = "idempotency"
+
+	// Hash of circuit breaker name to forced state, shared by every gateway
+	// instance.
+	CircuitBreakerForcedKey = "circuit_breaker:forced"
 )
@@ -10,11 +10,15 @@ import (
 	"time"
 
 	"github.com/Fl0rencess720/Doria/src/common/conf"
+	"github.com/Fl0rencess720/Doria/src/common/health"
 	"github.com/Fl0rencess720/Doria/src/common/logging"
+	"github.com/Fl0rencess720/Doria/src/common/metrics"
 	"github.com/Fl0rencess720/Doria/src/common/profiling"
 	"github.com/Fl0rencess720/Doria/src/common/registry"
+	"github.com/Fl0rencess720/Doria/src/common/shutdown"
 	"github.com/Fl0rencess720/Doria/src/common/tracing"
 	"github.com/Fl0rencess720/Doria/src/gateway/configs"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/jwtc"
 	"github.com/spf13/viper"
 
 	"github.com/cloudwego/eino-ext/callbacks/langfuse"
@@ -31,7 +35,11 @@ func init() {
 	conf.Init()
 	logging.Init()
 	profiling.InitPyroscope(configs.GetServiceName())
+	metrics.Serve(configs.GetServiceName())
 
+	if err := jwtc.Init(); err != nil {
+		zap.L().Panic("jwt keys init err", zap.Error(err))
+	}
 }
 
 func main() {
@@ -54,12 +62,13 @@ func main() {
 
 	callbacks.AppendGlobalHandlers(cbh)
 
-	if err := registerService(configs.GetServiceName()); err != nil {
+	app := wireApp()
+	app.HealthChecker.Start(context.Background())
+
+	if err := registerService(configs.GetServiceName(), app.HealthChecker.Err); err != nil {
 		zap.L().Panic("register service err: %s", zap.Error(err))
 	}
 
-	app := wireApp()
-
 	go func() {
 		if err := app.HttpServer.Server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			zap.L().Error("HTTP Server ListenAndServe", zap.Error(err))
@@ -74,10 +83,10 @@ func main() {
 		}
 	}()
 
-	closeServers(app.HttpServer.Server, app.SignalingServer.Server)
+	closeServers(app.HealthChecker, app.Shutdown, app.HttpServer.Server, app.SignalingServer.Server)
 }
 
-func registerService(serviceName string) error {
+func registerService(serviceName string, check func() error) error {
 	registrationManager := registry.NewRegistrationManager()
 	serviceID, err := registrationManager.RegisterService(serviceName)
 	if err != nil {
@@ -85,15 +94,24 @@ func registerService(serviceName string) error {
 	}
 	ID = serviceID
 
-	go registrationManager.SetTTLHealthCheck()
+	go registrationManager.SetTTLHealthCheck(check)
 	return nil
 }
 
-func closeServers(servers ...*http.Server) {
+func closeServers(checker *health.Checker, coordinator *shutdown.Coordinator, servers ...*http.Server) {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM, syscall.SIGTERM)
 	<-quit
 	zap.L().Info("Shutdown Servers ...")
+	checker.Shutdown()
+
+	// Streams are drained first while the listeners keep serving ordinary
+	// requests and the signaling traffic TTS pipelines still need.
+	if err := coordinator.Drain(context.Background()); err != nil {
+		zap.L().Warn("grace period expired before streams drained", zap.Error(err))
+	} else {
+		zap.L().Info("Streams drained")
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -8,30 +8,45 @@ package main
 import (
 	"github.com/google/wire"
 
+	"github.com/Fl0rencess720/Doria/src/common/health"
+	"github.com/Fl0rencess720/Doria/src/common/shutdown"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/biz"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/data"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/service"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/apispec"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/circuitbreaker"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/idempotency"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/ratelimit"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/resilience"
 )
 
 type App struct {
 	HttpServer      *service.HTTPServer
 	SignalingServer *service.SignalingServer
+	HealthChecker   *health.Checker
+	Shutdown        *shutdown.Coordinator
 }
 
-func NewApp(httpServer *service.HTTPServer, signalingServer *service.SignalingServer) *App {
+func NewApp(httpServer *service.HTTPServer, signalingServer *service.SignalingServer, healthChecker *health.Checker, coordinator *shutdown.Coordinator) *App {
 	return &App{
 		HttpServer:      httpServer,
 		SignalingServer: signalingServer,
+		HealthChecker:   healthChecker,
+		Shutdown:        coordinator,
 	}
 }
 
 func wireApp() *App {
 	panic(wire.Build(
 		NewApp,
+		shutdown.NewCoordinator,
 		service.ProviderSet,
 		biz.ProviderSet,
 		data.ProviderSet,
+		apispec.ProviderSet,
 		circuitbreaker.ProviderSet,
+		idempotency.ProviderSet,
+		ratelimit.ProviderSet,
+		resilience.ProviderSet,
 	))
 }
@@ -7,43 +7,73 @@
 package main
 
 import (
+	health2 "github.com/Fl0rencess720/Doria/src/common/health"
+	"github.com/Fl0rencess720/Doria/src/common/shutdown"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/biz"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/data"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/apispec"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/circuitbreaker"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/idempotency"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/ratelimit"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/resilience"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/service"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/admin"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/docs"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/health"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/image"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/mate"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/middlewares"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/openai"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/signaling"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/usage"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/user"
 )
 
 // Injectors from wire.go:
 
 func wireApp() *App {
-	ipRateLimiter := middlewares.NewDefaultIPRateLimiter()
+	client := data.NewRedis()
+	limiter := ratelimit.NewLimiter(client)
+	rules := ratelimit.NewRules()
+	rateLimiter := middlewares.NewRateLimiter(limiter, rules)
 	imageRepo := data.NewImageRepo()
-	imageServiceClient := data.NewImageClient()
-	circuitBreakerManager := circuitbreaker.NewCircuitBreakerManager()
+	policies := resilience.NewPolicies()
+	imageServiceClient := data.NewImageClient(policies)
+	circuitBreakerManager := circuitbreaker.NewCircuitBreakerManager(client)
 	imageUseCase := biz.NewImageUsecase(imageRepo, imageServiceClient, circuitBreakerManager)
 	imageHandler := image.NewImageHandler(imageUseCase)
-	userRepo := data.NewUserRepo()
-	userServiceClient := data.NewUserClient()
-	userUseCase := biz.NewUserUsecase(userRepo, userServiceClient, circuitBreakerManager)
+	userRepo := data.NewUserRepo(client)
+	usageRepo := data.NewUsageRepo(client)
+	userServiceClient := data.NewUserClient(policies)
+	userUseCase := biz.NewUserUsecase(userRepo, usageRepo, userServiceClient, circuitBreakerManager)
 	userHandler := user.NewUserHandler(userUseCase)
-	mateRepo := data.NewMateRepo()
-	mateServiceClient := data.NewMateClient()
-	mateUseCase := biz.NewMateUsecase(mateRepo, mateServiceClient, circuitBreakerManager)
+	mateRepo := data.NewMateRepo(client)
+	mateServiceClient := data.NewMateClient(policies)
+	coordinator := shutdown.NewCoordinator()
+	mateUseCase := biz.NewMateUsecase(mateRepo, mateServiceClient, circuitBreakerManager, coordinator)
 	ttsRepo := data.NewTTSRepo()
-	ttsServiceClient := data.NewTTSClient()
+	ttsServiceClient := data.NewTTSClient(policies)
 	ttsUseCase := biz.NewTTSUsecase(ttsRepo, ttsServiceClient, circuitBreakerManager)
-	mateHandler := mate.NewMateHandler(mateUseCase, ttsUseCase)
-	httpServer := service.NewHTTPServer(ipRateLimiter, imageHandler, userHandler, mateHandler)
+	usageUseCase := biz.NewUsageUsecase(usageRepo)
+	mateHandler := mate.NewMateHandler(mateUseCase, ttsUseCase, usageUseCase, rateLimiter, coordinator)
+	openAIHandler := openai.NewOpenAIHandler(mateUseCase)
+	usageHandler := usage.NewUsageHandler(usageUseCase)
+	memoryServiceClient := data.NewMemoryClient(policies)
+	adminUseCase := biz.NewAdminUsecase(userRepo, userServiceClient, memoryServiceClient, circuitBreakerManager)
+	healthRepo := data.NewHealthRepo()
+	checker := data.NewHealthChecker(client)
+	healthUseCase := biz.NewHealthUsecase(healthRepo, checker)
+	adminHandler := admin.NewAdminHandler(circuitBreakerManager, adminUseCase, mateUseCase, healthUseCase)
+	healthHandler := health.NewHealthHandler(healthUseCase)
+	spec := apispec.NewSpec()
+	docsHandler := docs.NewDocsHandler(spec)
+	store := idempotency.NewStore(client)
+	httpServer := service.NewHTTPServer(rateLimiter, imageHandler, userHandler, mateHandler, openAIHandler, usageHandler, adminHandler, healthHandler, docsHandler, userUseCase, usageUseCase, adminUseCase, store, spec, coordinator)
 	signalingRepo := data.NewSignalingRepo()
 	signalingUseCase := biz.NewSignalingUsecase(signalingRepo)
-	signalingHandler := signaling.NewSignalingHandler(signalingUseCase)
-	signalingServer := service.NewSignalingServer(signalingHandler)
-	app := NewApp(httpServer, signalingServer)
+	signalingHandler := signaling.NewSignalingHandler(signalingUseCase, coordinator)
+	signalingServer := service.NewSignalingServer(signalingHandler, signalingUseCase, coordinator)
+	app := NewApp(httpServer, signalingServer, checker, coordinator)
 	return app
 }
 
@@ -52,11 +82,15 @@ func wireApp() *App {
 type App struct {
 	HttpServer      *service.HTTPServer
 	SignalingServer *service.SignalingServer
+	HealthChecker   *health2.Checker
+	Shutdown        *shutdown.Coordinator
 }
 
-func NewApp(httpServer *service.HTTPServer, signalingServer *service.SignalingServer) *App {
+func NewApp(httpServer *service.HTTPServer, signalingServer *service.SignalingServer, healthChecker *health2.Checker, coordinator *shutdown.Coordinator) *App {
 	return &App{
 		HttpServer:      httpServer,
 		SignalingServer: signalingServer,
+		HealthChecker:   healthChecker,
+		Shutdown:        coordinator,
 	}
 }
@@ -0,0 +1,269 @@
+package biz
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/models"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/circuitbreaker"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/response"
+	memoryapi "github.com/Fl0rencess720/Doria/src/rpc/memory"
+	userapi "github.com/Fl0rencess720/Doria/src/rpc/user"
+	"go.uber.org/zap"
+)
+
+type adminUseCase struct {
+	userRepo       UserRepo
+	userClient     userapi.UserServiceClient
+	memoryClient   memoryapi.MemoryServiceClient
+	circuitBreaker *circuitbreaker.CircuitBreakerManager
+}
+
+func NewAdminUsecase(userRepo UserRepo, userClient userapi.UserServiceClient,
+	memoryClient memoryapi.MemoryServiceClient, cbManager *circuitbreaker.CircuitBreakerManager) AdminUseCase {
+	return &adminUseCase{
+		userRepo:       userRepo,
+		userClient:     userClient,
+		memoryClient:   memoryClient,
+		circuitBreaker: cbManager,
+	}
+}
+
+func (u *adminUseCase) ListUsers(ctx context.Context, req *models.ListUsersReq) (*models.ListUsersResp, response.ErrorCode, error) {
+	result, err := u.circuitBreaker.Do(ctx, "user-service.ListUsers",
+		func(ctx context.Context) (any, error) {
+			return u.userClient.ListUsers(ctx, &userapi.ListUsersRequest{
+				Query:    req.Query,
+				Page:     int32(req.Page),
+				PageSize: int32(req.PageSize),
+			})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("list users fallback triggered", zap.Error(err))
+			return nil, err
+		},
+	)
+
+	if err != nil {
+		zap.L().Error("list users error", zap.Error(err))
+		return nil, response.FromError(err), err
+	}
+
+	switch v := result.(type) {
+	case *userapi.ListUsersResponse:
+		users := make([]models.AdminUserResp, len(v.Users))
+		for i, user := range v.Users {
+			users[i] = models.AdminUserResp{
+				ID:        user.Id,
+				Username:  user.Username,
+				Phone:     user.Phone,
+				Status:    user.Status,
+				Role:      user.Role,
+				Disabled:  user.Disabled,
+				CreatedAt: user.CreatedAt,
+			}
+		}
+		return &models.ListUsersResp{
+			Users: users,
+			Total: v.Total,
+		}, response.NoError, nil
+	default:
+		return nil, response.ServerError, fmt.Errorf("unexpected response type")
+	}
+}
+
+// SetUserDisabled flips the account flag and, when disabling, revokes every
+// session so access tokens stop passing Auth right away instead of at expiry.
+func (u *adminUseCase) SetUserDisabled(ctx context.Context, userID int, disabled bool) (response.ErrorCode, error) {
+	_, err := u.circuitBreaker.Do(ctx, "user-service.SetUserDisabled",
+		func(ctx context.Context) (any, error) {
+			return u.userClient.SetUserDisabled(ctx, &userapi.SetUserDisabledRequest{
+				UserId:   int32(userID),
+				Disabled: disabled,
+			})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("set user disabled fallback triggered", zap.Error(err))
+			return nil, err
+		},
+	)
+
+	if err != nil {
+		zap.L().Error("set user disabled error", zap.Error(err))
+		return response.FromError(err), err
+	}
+
+	if disabled {
+		if err := u.userRepo.RevokeAllSessions(ctx, userID); err != nil {
+			zap.L().Error("revoke all sessions error", zap.Error(err))
+			return response.ServerError, err
+		}
+	}
+
+	return response.NoError, nil
+}
+
+func (u *adminUseCase) SetUserRole(ctx context.Context, userID int, role string) (response.ErrorCode, error) {
+	_, err := u.circuitBreaker.Do(ctx, "user-service.SetUserRole",
+		func(ctx context.Context) (any, error) {
+			return u.userClient.SetUserRole(ctx, &userapi.SetUserRoleRequest{
+				UserId: int32(userID),
+				Role:   role,
+			})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("set user role fallback triggered", zap.Error(err))
+			return nil, err
+		},
+	)
+
+	if err != nil {
+		zap.L().Error("set user role error", zap.Error(err))
+		return response.FromError(err), err
+	}
+
+	return response.NoError, nil
+}
+
+func (u *adminUseCase) GetMemoryTiers(ctx context.Context, userID int) (*models.MemoryTiersResp, response.ErrorCode, error) {
+	result, err := u.circuitBreaker.Do(ctx, "memory-service.GetMemoryTiers",
+		func(ctx context.Context) (any, error) {
+			return u.memoryClient.GetMemoryTiers(ctx, &memoryapi.GetMemoryTiersRequest{
+				UserId: int32(userID),
+			})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("get memory tiers fallback triggered", zap.Error(err))
+			return nil, err
+		},
+	)
+
+	if err != nil {
+		zap.L().Error("get memory tiers error", zap.Error(err))
+		return nil, response.FromError(err), err
+	}
+
+	switch v := result.(type) {
+	case *memoryapi.GetMemoryTiersResponse:
+		tiers := &models.MemoryTiersResp{
+			ShortTerm: make([]models.MemoryPageResp, len(v.ShortTerm)),
+			MidTerm:   make([]models.MemorySegmentResp, len(v.MidTerm)),
+			LongTerm:  make([]models.MemoryKnowledgeResp, len(v.LongTerm)),
+		}
+		for i, page := range v.ShortTerm {
+			tiers.ShortTerm[i] = models.MemoryPageResp{
+				ID:             page.Id,
+				ConversationID: page.ConversationId,
+				UserInput:      page.UserInput,
+				AgentOutput:    page.AgentOutput,
+				CreateTime:     page.CreateTime,
+			}
+		}
+		for i, segment := range v.MidTerm {
+			tiers.MidTerm[i] = models.MemorySegmentResp{
+				ID:        segment.Id,
+				Overview:  segment.Overview,
+				Visit:     segment.Visit,
+				LastVisit: segment.LastVisit,
+				PageCount: segment.PageCount,
+			}
+		}
+		for i, knowledge := range v.LongTerm {
+			tiers.LongTerm[i] = models.MemoryKnowledgeResp{
+				ID:      knowledge.Id,
+				Content: knowledge.Content,
+			}
+		}
+		return tiers, response.NoError, nil
+	default:
+		return nil, response.ServerError, fmt.Errorf("unexpected response type")
+	}
+}
+
+func (u *adminUseCase) ReprocessMemory(ctx context.Context, userID int) (response.ErrorCode, error) {
+	_, err := u.circuitBreaker.Do(ctx, "memory-service.ReprocessMemory",
+		func(ctx context.Context) (any, error) {
+			return u.memoryClient.ReprocessMemory(ctx, &memoryapi.ReprocessMemoryRequest{
+				UserId: int32(userID),
+			})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("reprocess memory fallback triggered", zap.Error(err))
+			return nil, err
+		},
+	)
+
+	if err != nil {
+		zap.L().Error("reprocess memory error", zap.Error(err))
+		return response.FromError(err), err
+	}
+
+	return response.NoError, nil
+}
+
+func (u *adminUseCase) RecordAudit(ctx context.Context, log *models.AuditLog) error {
+	_, err := u.circuitBreaker.Do(ctx, "user-service.RecordAudit",
+		func(ctx context.Context) (any, error) {
+			return u.userClient.RecordAudit(ctx, &userapi.RecordAuditRequest{
+				Log: &userapi.AuditLog{
+					ActorId: int32(log.ActorID),
+					Action:  log.Action,
+					Target:  log.Target,
+					Status:  int32(log.Status),
+					Ip:      log.IP,
+					Detail:  log.Detail,
+				},
+			})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("record audit fallback triggered", zap.Error(err))
+			return nil, err
+		},
+	)
+
+	return err
+}
+
+func (u *adminUseCase) ListAuditLogs(ctx context.Context, req *models.ListAuditLogsReq) (*models.ListAuditLogsResp, response.ErrorCode, error) {
+	result, err := u.circuitBreaker.Do(ctx, "user-service.ListAuditLogs",
+		func(ctx context.Context) (any, error) {
+			return u.userClient.ListAuditLogs(ctx, &userapi.ListAuditLogsRequest{
+				ActorId:  int32(req.ActorID),
+				Page:     int32(req.Page),
+				PageSize: int32(req.PageSize),
+			})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("list audit logs fallback triggered", zap.Error(err))
+			return nil, err
+		},
+	)
+
+	if err != nil {
+		zap.L().Error("list audit logs error", zap.Error(err))
+		return nil, response.FromError(err), err
+	}
+
+	switch v := result.(type) {
+	case *userapi.ListAuditLogsResponse:
+		logs := make([]models.AuditLog, len(v.Logs))
+		for i, log := range v.Logs {
+			logs[i] = models.AuditLog{
+				ID:        log.Id,
+				ActorID:   int(log.ActorId),
+				Action:    log.Action,
+				Target:    log.Target,
+				Status:    int(log.Status),
+				IP:        log.Ip,
+				Detail:    log.Detail,
+				CreatedAt: log.CreatedAt,
+			}
+		}
+		return &models.ListAuditLogsResp{
+			Logs:  logs,
+			Total: v.Total,
+		}, response.NoError, nil
+	default:
+		return nil, response.ServerError, fmt.Errorf("unexpected response type")
+	}
+}
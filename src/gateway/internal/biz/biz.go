@@ -3,4 +3,4 @@ package biz
 import "github.com/google/wire"
 
 var ProviderSet = wire.NewSet(NewImageUsecase, NewUserUsecase,
-	NewTTSUsecase, NewMateUsecase, NewSignalingUsecase)
+	NewTTSUsecase, NewMateUsecase, NewSignalingUsecase, NewUsageUsecase, NewHealthUsecase, NewAdminUsecase)
@@ -0,0 +1,159 @@
+package biz
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/models"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/response"
+	mateapi "github.com/Fl0rencess720/Doria/src/rpc/mate"
+	"go.uber.org/zap"
+)
+
+func (u *mateUseCase) CreateConversation(ctx context.Context, req *models.CreateConversationReq, userID int) (*models.ConversationResp, response.ErrorCode, error) {
+	result, err := u.circuitBreaker.Do(ctx, "mate-service.CreateConversation",
+		func(ctx context.Context) (any, error) {
+			return u.mateClient.CreateConversation(ctx, &mateapi.CreateConversationRequest{
+				UserId: int32(userID),
+				Title:  req.Title,
+			})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("mate CreateConversation fallback triggered", zap.Error(err))
+			return nil, err
+		},
+	)
+
+	if err != nil {
+		zap.L().Error("CreateConversation error", zap.Error(err))
+		return nil, response.FromError(err), err
+	}
+
+	switch v := result.(type) {
+	case *mateapi.CreateConversationResponse:
+		return toConversationResp(v.Conversation), response.NoError, nil
+	default:
+		return nil, response.ServerError, fmt.Errorf("unexpected response type")
+	}
+}
+
+func (u *mateUseCase) ListConversations(ctx context.Context, userID int, includeArchived bool) ([]models.ConversationResp, response.ErrorCode, error) {
+	result, err := u.circuitBreaker.Do(ctx, "mate-service.ListConversations",
+		func(ctx context.Context) (any, error) {
+			return u.mateClient.ListConversations(ctx, &mateapi.ListConversationsRequest{
+				UserId:          int32(userID),
+				IncludeArchived: includeArchived,
+			})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("mate ListConversations fallback triggered", zap.Error(err))
+			return []models.ConversationResp{}, nil
+		},
+	)
+
+	if err != nil {
+		zap.L().Error("ListConversations error", zap.Error(err))
+		return nil, response.FromError(err), err
+	}
+
+	switch v := result.(type) {
+	case *mateapi.ListConversationsResponse:
+		conversations := make([]models.ConversationResp, len(v.Conversations))
+		for i, conversation := range v.Conversations {
+			conversations[i] = *toConversationResp(conversation)
+		}
+		return conversations, response.NoError, nil
+	case []models.ConversationResp:
+		return v, response.DegradedError, nil
+	default:
+		return nil, response.ServerError, fmt.Errorf("unexpected response type")
+	}
+}
+
+func (u *mateUseCase) RenameConversation(ctx context.Context, req *models.RenameConversationReq, userID int, conversationID uint) (*models.ConversationResp, response.ErrorCode, error) {
+	result, err := u.circuitBreaker.Do(ctx, "mate-service.RenameConversation",
+		func(ctx context.Context) (any, error) {
+			return u.mateClient.RenameConversation(ctx, &mateapi.RenameConversationRequest{
+				UserId:         int32(userID),
+				ConversationId: uint32(conversationID),
+				Title:          req.Title,
+			})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("mate RenameConversation fallback triggered", zap.Error(err))
+			return nil, err
+		},
+	)
+
+	if err != nil {
+		zap.L().Error("RenameConversation error", zap.Error(err))
+		return nil, response.FromError(err), err
+	}
+
+	switch v := result.(type) {
+	case *mateapi.RenameConversationResponse:
+		return toConversationResp(v.Conversation), response.NoError, nil
+	default:
+		return nil, response.ServerError, fmt.Errorf("unexpected response type")
+	}
+}
+
+func (u *mateUseCase) ArchiveConversation(ctx context.Context, req *models.ArchiveConversationReq, userID int, conversationID uint) (*models.ConversationResp, response.ErrorCode, error) {
+	result, err := u.circuitBreaker.Do(ctx, "mate-service.ArchiveConversation",
+		func(ctx context.Context) (any, error) {
+			return u.mateClient.ArchiveConversation(ctx, &mateapi.ArchiveConversationRequest{
+				UserId:         int32(userID),
+				ConversationId: uint32(conversationID),
+				Archived:       *req.Archived,
+			})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("mate ArchiveConversation fallback triggered", zap.Error(err))
+			return nil, err
+		},
+	)
+
+	if err != nil {
+		zap.L().Error("ArchiveConversation error", zap.Error(err))
+		return nil, response.FromError(err), err
+	}
+
+	switch v := result.(type) {
+	case *mateapi.ArchiveConversationResponse:
+		return toConversationResp(v.Conversation), response.NoError, nil
+	default:
+		return nil, response.ServerError, fmt.Errorf("unexpected response type")
+	}
+}
+
+func (u *mateUseCase) DeleteConversation(ctx context.Context, userID int, conversationID uint) (response.ErrorCode, error) {
+	_, err := u.circuitBreaker.Do(ctx, "mate-service.DeleteConversation",
+		func(ctx context.Context) (any, error) {
+			return u.mateClient.DeleteConversation(ctx, &mateapi.DeleteConversationRequest{
+				UserId:         int32(userID),
+				ConversationId: uint32(conversationID),
+			})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("mate DeleteConversation fallback triggered", zap.Error(err))
+			return nil, err
+		},
+	)
+
+	if err != nil {
+		zap.L().Error("DeleteConversation error", zap.Error(err))
+		return response.FromError(err), err
+	}
+
+	return response.NoError, nil
+}
+
+func toConversationResp(conversation *mateapi.Conversation) *models.ConversationResp {
+	return &models.ConversationResp{
+		ID:         uint(conversation.GetId()),
+		Title:      conversation.GetTitle(),
+		Archived:   conversation.GetArchived(),
+		CreateTime: conversation.GetCreateTime(),
+		UpdateTime: conversation.GetUpdateTime(),
+	}
+}
@@ -0,0 +1,141 @@
+package biz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/models"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/jwtc"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/response"
+	userapi "github.com/Fl0rencess720/Doria/src/rpc/user"
+	"go.uber.org/zap"
+)
+
+const deletionCompleted = "completed"
+
+// DeleteAccount queues the erasure of the caller's account. The user service
+// disables the account right away; the sessions and quota plan kept here go
+// with it, and the purge of every store runs in the background.
+func (u *userUseCase) DeleteAccount(ctx context.Context, userID int, req *models.DeleteAccountReq) (*models.DeleteAccountResp, response.ErrorCode, error) {
+	result, err := u.circuitBreaker.Do(ctx, "user-service.DeleteAccount",
+		func(ctx context.Context) (any, error) {
+			return u.userClient.DeleteAccount(ctx, &userapi.DeleteAccountRequest{
+				UserId:   int32(userID),
+				Password: req.Password,
+			})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("delete account fallback triggered", zap.Error(err))
+			return nil, err
+		},
+	)
+
+	if err != nil {
+		zap.L().Error("delete account error", zap.Error(err))
+		return nil, response.FromError(err), err
+	}
+
+	v, ok := result.(*userapi.DeleteAccountResponse)
+	if !ok {
+		return nil, response.ServerError, fmt.Errorf("unexpected response type")
+	}
+
+	if err := u.repo.RevokeAllSessions(ctx, userID); err != nil {
+		zap.L().Error("revoke all sessions error", zap.Int("userID", userID), zap.Error(err))
+	}
+	if err := u.usageRepo.SetUserPlan(ctx, userID, ""); err != nil {
+		zap.L().Error("reset user plan error", zap.Int("userID", userID), zap.Error(err))
+	}
+
+	return &models.DeleteAccountResp{
+		DeletionID: v.DeletionId,
+		Token:      v.Token,
+	}, response.NoError, nil
+}
+
+// GetAccountDeletion reports the purge progress to whoever holds the status
+// token. Once every step is done it attaches a signed receipt, unless the
+// gateway signs with HS256, which cannot produce one others can verify.
+func (u *userUseCase) GetAccountDeletion(ctx context.Context, deletionID string, req *models.GetAccountDeletionReq) (*models.AccountDeletion, response.ErrorCode, error) {
+	result, err := u.circuitBreaker.Do(ctx, "user-service.GetAccountDeletion",
+		func(ctx context.Context) (any, error) {
+			return u.userClient.GetAccountDeletion(ctx, &userapi.GetAccountDeletionRequest{
+				DeletionId: deletionID,
+				Token:      req.Token,
+			})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("get account deletion fallback triggered", zap.Error(err))
+			return nil, err
+		},
+	)
+
+	if err != nil {
+		zap.L().Error("get account deletion error", zap.Error(err))
+		return nil, response.FromError(err), err
+	}
+
+	v, ok := result.(*userapi.GetAccountDeletionResponse)
+	if !ok {
+		return nil, response.ServerError, fmt.Errorf("unexpected response type")
+	}
+
+	deletion := toAccountDeletion(v.Deletion)
+	if deletion.Status == deletionCompleted {
+		receipt, err := genDeletionReceipt(v.Deletion)
+		if errors.Is(err, jwtc.ErrReceiptUnavailable) {
+			return deletion, response.NoError, nil
+		}
+		if err != nil {
+			zap.L().Error("generate deletion receipt error", zap.Error(err))
+			return nil, response.ServerError, err
+		}
+		deletion.Receipt = receipt
+	}
+
+	return deletion, response.NoError, nil
+}
+
+func toAccountDeletion(deletion *userapi.AccountDeletion) *models.AccountDeletion {
+	resp := &models.AccountDeletion{
+		ID:          deletion.GetId(),
+		Status:      deletion.GetStatus(),
+		RequestedAt: deletion.GetRequestedAt(),
+		CompletedAt: deletion.GetCompletedAt(),
+		Steps:       make([]*models.AccountDeletionStep, len(deletion.GetSteps())),
+	}
+	for i, step := range deletion.GetSteps() {
+		resp.Steps[i] = &models.AccountDeletionStep{
+			Name:        step.GetName(),
+			Status:      step.GetStatus(),
+			CompletedAt: step.GetCompletedAt(),
+		}
+		if step.GetDetail() != "" {
+			if err := json.Unmarshal([]byte(step.GetDetail()), &resp.Steps[i].Detail); err != nil {
+				zap.L().Warn("decode deletion step detail error", zap.String("step", step.GetName()), zap.Error(err))
+			}
+		}
+	}
+	return resp
+}
+
+func genDeletionReceipt(deletion *userapi.AccountDeletion) (string, error) {
+	steps := make([]jwtc.ReceiptStep, len(deletion.GetSteps()))
+	for i, step := range deletion.GetSteps() {
+		steps[i] = jwtc.ReceiptStep{
+			Name:        step.GetName(),
+			CompletedAt: step.GetCompletedAt(),
+		}
+	}
+
+	return jwtc.GenDeletionReceipt(
+		deletion.GetId(),
+		int(deletion.GetUserId()),
+		time.Unix(deletion.GetRequestedAt(), 0),
+		time.Unix(deletion.GetCompletedAt(), 0),
+		steps,
+	)
+}
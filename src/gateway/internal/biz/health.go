@@ -0,0 +1,54 @@
+package biz
+
+import (
+	"context"
+	"time"
+
+	"github.com/Fl0rencess720/Doria/src/common/health"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/models"
+)
+
+const downstreamCheckTimeout = 500 * time.Millisecond
+
+type HealthRepo interface {
+	CheckDownstream(ctx context.Context) []*models.DownstreamStatus
+}
+
+type healthUseCase struct {
+	repo    HealthRepo
+	checker *health.Checker
+}
+
+func NewHealthUsecase(repo HealthRepo, checker *health.Checker) HealthUseCase {
+	return &healthUseCase{
+		repo:    repo,
+		checker: checker,
+	}
+}
+
+// Check reports the gateway's own dependencies and the health of every
+// backend. Only the former decide readiness: an unhealthy backend is already
+// handled by its circuit breaker, so it only degrades the status.
+func (u *healthUseCase) Check(ctx context.Context) *models.HealthResp {
+	report := u.checker.Report()
+
+	ctx, cancel := context.WithTimeout(ctx, downstreamCheckTimeout)
+	defer cancel()
+	downstream := u.repo.CheckDownstream(ctx)
+
+	status := report.Status
+	if status == health.StatusUp {
+		for _, d := range downstream {
+			if d.Status != health.StatusUp {
+				status = models.HealthStatusDegraded
+				break
+			}
+		}
+	}
+
+	return &models.HealthResp{
+		Status:     status,
+		Checks:     report.Checks,
+		Downstream: downstream,
+	}
+}
@@ -62,7 +62,7 @@ func (u *imageUseCase) GenerateText(ctx context.Context, req *models.GenerateReq
 
 	if err != nil {
 		zap.L().Error("generate text on image failed", zap.Error(err))
-		return nil, response.ServerError, err
+		return nil, response.FromError(err), err
 	}
 
 	switch v := result.(type) {
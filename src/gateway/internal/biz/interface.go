@@ -13,7 +13,32 @@ import (
 type UserUseCase interface {
 	Register(ctx context.Context, req *models.UserRegisterReq) (*models.UserRegisterResp, response.ErrorCode, error)
 	Login(ctx context.Context, req *models.UserLoginReq) (*models.UserLoginResp, response.ErrorCode, error)
+	SendVerificationCode(ctx context.Context, req *models.SendCodeReq, clientIP string) (*models.SendCodeResp, response.ErrorCode, error)
+	ChangePhone(ctx context.Context, userID int, req *models.ChangePhoneReq) (*models.UserLoginResp, response.ErrorCode, error)
+	RequestPasswordReset(ctx context.Context, req *models.RequestPasswordResetReq, clientIP string) (*models.SendCodeResp, response.ErrorCode, error)
+	ResetPassword(ctx context.Context, req *models.ResetPasswordReq) (*models.UserLoginResp, response.ErrorCode, error)
+	ChangePassword(ctx context.Context, userID int, req *models.ChangePasswordReq) (*models.UserLoginResp, response.ErrorCode, error)
+	GuestLogin(ctx context.Context, req *models.GuestLoginReq) (*models.UserLoginResp, response.ErrorCode, error)
+	UpgradeGuest(ctx context.Context, userID int, req *models.UpgradeGuestReq) (*models.UserLoginResp, response.ErrorCode, error)
+	GetProfile(ctx context.Context, userID int) (*models.UserProfile, response.ErrorCode, error)
+	UpdateProfile(ctx context.Context, userID int, req *models.UpdateProfileReq) (*models.UserProfile, response.ErrorCode, error)
+	DeleteAccount(ctx context.Context, userID int, req *models.DeleteAccountReq) (*models.DeleteAccountResp, response.ErrorCode, error)
+	GetAccountDeletion(ctx context.Context, deletionID string, req *models.GetAccountDeletionReq) (*models.AccountDeletion, response.ErrorCode, error)
 	Refresh(ctx context.Context, req *models.UserRefreshReq) (*models.UserRefreshResp, response.ErrorCode, error)
+	Logout(ctx context.Context, userID int, sessionID string) (response.ErrorCode, error)
+	LogoutAll(ctx context.Context, userID int) (response.ErrorCode, error)
+	ValidateSession(ctx context.Context, userID int, sessionID string) (bool, error)
+	GetAccess(ctx context.Context, userID int) (*models.UserAccess, response.ErrorCode, error)
+}
+
+type AdminUseCase interface {
+	ListUsers(ctx context.Context, req *models.ListUsersReq) (*models.ListUsersResp, response.ErrorCode, error)
+	SetUserDisabled(ctx context.Context, userID int, disabled bool) (response.ErrorCode, error)
+	SetUserRole(ctx context.Context, userID int, role string) (response.ErrorCode, error)
+	GetMemoryTiers(ctx context.Context, userID int) (*models.MemoryTiersResp, response.ErrorCode, error)
+	ReprocessMemory(ctx context.Context, userID int) (response.ErrorCode, error)
+	RecordAudit(ctx context.Context, log *models.AuditLog) error
+	ListAuditLogs(ctx context.Context, req *models.ListAuditLogsReq) (*models.ListAuditLogsResp, response.ErrorCode, error)
 }
 
 type TTSUseCase interface {
@@ -25,9 +50,28 @@ type ImageUseCase interface {
 }
 
 type MateUseCase interface {
-	Chat(ctx context.Context, req *models.ChatReq, userID int) (string, response.ErrorCode, error)
+	Chat(ctx context.Context, req *models.ChatReq, userID int) (*mateapi.ChatResponse, response.ErrorCode, error)
 	CreateChatStream(ctx context.Context, req *models.ChatReq, userID int) (mateapi.MateService_ChatStreamClient, error)
+	StartChatStream(ctx context.Context, req *models.ChatReq, userID int) (string, error)
+	ReadChatStream(ctx context.Context, userID int, streamID string, afterSeq int64) ([]*models.ChatStreamChunk, error)
 	GetUserPages(ctx context.Context, req *models.GetUserPagesRequest) (*models.GetUserPagesResponse, response.ErrorCode, error)
+	GetConversationMessages(ctx context.Context, req *models.GetConversationMessagesReq) ([]models.MessageResp, response.ErrorCode, error)
+	CreateConversation(ctx context.Context, req *models.CreateConversationReq, userID int) (*models.ConversationResp, response.ErrorCode, error)
+	ListConversations(ctx context.Context, userID int, includeArchived bool) ([]models.ConversationResp, response.ErrorCode, error)
+	RenameConversation(ctx context.Context, req *models.RenameConversationReq, userID int, conversationID uint) (*models.ConversationResp, response.ErrorCode, error)
+	ArchiveConversation(ctx context.Context, req *models.ArchiveConversationReq, userID int, conversationID uint) (*models.ConversationResp, response.ErrorCode, error)
+	DeleteConversation(ctx context.Context, userID int, conversationID uint) (response.ErrorCode, error)
+}
+
+type UsageUseCase interface {
+	// guest is true for callers holding a guest-scoped token, who are on
+	// the guest plan regardless of any plan assigned to the user.
+	GetUsage(ctx context.Context, userID int, guest bool) (*models.UsageResp, response.ErrorCode, error)
+	CheckQuota(ctx context.Context, userID int, guest bool) (response.ErrorCode, error)
+}
+
+type HealthUseCase interface {
+	Check(ctx context.Context) *models.HealthResp
 }
 
 type SignalingUseCase interface {
@@ -37,4 +81,5 @@ type SignalingUseCase interface {
 	UnregisterOfferPeer(ctx context.Context, peerID string) error
 	HandleAnswerPeerMessages(ctx context.Context, conn *websocket.Conn, sourcePeerID string) error
 	HandleOfferPeerMessages(ctx context.Context, conn *websocket.Conn, sourcePeerID string) error
+	ClosePeers(ctx context.Context)
 }
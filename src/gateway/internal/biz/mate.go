@@ -2,41 +2,57 @@ package biz
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"time"
 
+	"github.com/Fl0rencess720/Doria/src/common/shutdown"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/models"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/circuitbreaker"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/response"
 	mateapi "github.com/Fl0rencess720/Doria/src/rpc/mate"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/metadata"
 )
 
 type MateRepo interface {
+	AppendStreamChunk(ctx context.Context, userID int, streamID string, chunk *models.ChatStreamChunk) error
+	ReadStreamChunks(ctx context.Context, userID int, streamID string, afterSeq int64, block time.Duration) ([]*models.ChatStreamChunk, error)
+	StreamExists(ctx context.Context, userID int, streamID string) (bool, error)
 }
 
+const (
+	chatStreamGenerateTimeout = 10 * time.Minute
+	chatStreamReadBlock       = 15 * time.Second
+	// chatStreamFinalAppendTimeout bounds writing the last chunk of a stream,
+	// which must not depend on the generation context that may have ended it.
+	chatStreamFinalAppendTimeout = 5 * time.Second
+)
+
+var ErrChatStreamExpired = errors.New("chat stream expired")
+
 type mateUseCase struct {
 	repo           MateRepo
 	mateClient     mateapi.MateServiceClient
 	circuitBreaker *circuitbreaker.CircuitBreakerManager
+	shutdown       *shutdown.Coordinator
 }
 
-func NewMateUsecase(repo MateRepo, mateClient mateapi.MateServiceClient, cbManager *circuitbreaker.CircuitBreakerManager) MateUseCase {
+func NewMateUsecase(repo MateRepo, mateClient mateapi.MateServiceClient, cbManager *circuitbreaker.CircuitBreakerManager, coordinator *shutdown.Coordinator) MateUseCase {
 	return &mateUseCase{
 		repo:           repo,
 		mateClient:     mateClient,
 		circuitBreaker: cbManager,
+		shutdown:       coordinator,
 	}
 }
 
-func (u *mateUseCase) Chat(ctx context.Context, req *models.ChatReq, userID int) (string, response.ErrorCode, error) {
+func (u *mateUseCase) Chat(ctx context.Context, req *models.ChatReq, userID int) (*mateapi.ChatResponse, response.ErrorCode, error) {
 	result, err := u.circuitBreaker.Do(ctx, "mate-service.Chat",
 		func(ctx context.Context) (any, error) {
-			return u.mateClient.Chat(ctx, &mateapi.ChatRequest{
-				UserId: int32(userID),
-				Prompt: req.Prompt,
-			})
+			return u.mateClient.Chat(ctx, newChatRequest(req, userID))
 		},
 		func(ctx context.Context, err error) (any, error) {
 			zap.L().Error("mate fallback triggered", zap.Error(err))
@@ -46,26 +62,23 @@ func (u *mateUseCase) Chat(ctx context.Context, req *models.ChatReq, userID int)
 
 	if err != nil {
 		zap.L().Error("chat error", zap.Error(err))
-		return "", response.ServerError, err
+		return nil, response.FromError(err), err
 	}
 
 	switch v := result.(type) {
 	case *mateapi.ChatResponse:
-		return v.Message, response.NoError, nil
+		return v, response.NoError, nil
 	case string:
-		return v, response.DegradedError, nil
+		return &mateapi.ChatResponse{Message: v}, response.DegradedError, nil
 	default:
-		return "", response.ServerError, fmt.Errorf("unexpected response type")
+		return nil, response.ServerError, fmt.Errorf("unexpected response type")
 	}
 }
 
 func (u *mateUseCase) CreateChatStream(ctx context.Context, req *models.ChatReq, userID int) (mateapi.MateService_ChatStreamClient, error) {
 	result, err := u.circuitBreaker.Do(ctx, "mate-service.ChatStream",
 		func(ctx context.Context) (any, error) {
-			stream, err := u.mateClient.ChatStream(ctx, &mateapi.ChatRequest{
-				UserId: int32(userID),
-				Prompt: req.Prompt,
-			})
+			stream, err := u.mateClient.ChatStream(ctx, newChatRequest(req, userID))
 			if err != nil {
 				return nil, err
 			}
@@ -110,13 +123,108 @@ func (u *mateUseCase) CreateChatStream(ctx context.Context, req *models.ChatReq,
 	}
 }
 
+func (u *mateUseCase) StartChatStream(ctx context.Context, req *models.ChatReq, userID int) (string, error) {
+	streamID := uuid.New().String()
+
+	// The buffered stream outlives the request so clients can resume it, and
+	// on shutdown it keeps filling the buffer until the grace period ends.
+	done, ok := u.shutdown.Track()
+	if !ok {
+		return "", shutdown.ErrDraining
+	}
+	genCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), chatStreamGenerateTimeout)
+	stopOnShutdown := context.AfterFunc(u.shutdown.Context(), cancel)
+
+	stream, err := u.CreateChatStream(genCtx, req, userID)
+	if err != nil {
+		stopOnShutdown()
+		cancel()
+		done()
+		return "", err
+	}
+
+	go func() {
+		defer done()
+		defer stopOnShutdown()
+		defer cancel()
+
+		var (
+			seq       int64
+			messageID string
+		)
+		for {
+			resp, err := stream.Recv()
+
+			seq++
+			chunk := &models.ChatStreamChunk{Seq: seq, Timestamp: time.Now().Unix()}
+			switch {
+			case err == io.EOF:
+				// The backend closed without a finished message; mark the end
+				// anyway so replay readers stop instead of waiting for the TTL.
+				chunk.Type = "chunk"
+				chunk.MessageID = messageID
+				chunk.Finished = true
+			case err != nil:
+				zap.L().Error("failed to receive from gRPC stream", zap.String("stream_id", streamID), zap.Error(err))
+				chunk.Type = "error"
+				chunk.Error = err.Error()
+			default:
+				messageID = resp.MessageId
+				chunk.Type = "chunk"
+				chunk.Content = resp.Content
+				chunk.MessageID = resp.MessageId
+				chunk.Timestamp = resp.Timestamp
+				chunk.Finished = resp.Finished
+			}
+
+			final := chunk.Finished || chunk.Type == "error"
+			appendCtx, cancelAppend := genCtx, context.CancelFunc(func() {})
+			if final {
+				appendCtx, cancelAppend = context.WithTimeout(context.WithoutCancel(genCtx), chatStreamFinalAppendTimeout)
+			}
+			err = u.repo.AppendStreamChunk(appendCtx, userID, streamID, chunk)
+			cancelAppend()
+			if err != nil {
+				zap.L().Error("failed to buffer stream chunk", zap.String("stream_id", streamID), zap.Error(err))
+				return
+			}
+
+			if final {
+				return
+			}
+		}
+	}()
+
+	return streamID, nil
+}
+
+func (u *mateUseCase) ReadChatStream(ctx context.Context, userID int, streamID string, afterSeq int64) ([]*models.ChatStreamChunk, error) {
+	chunks, err := u.repo.ReadStreamChunks(ctx, userID, streamID, afterSeq, chatStreamReadBlock)
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) > 0 {
+		return chunks, nil
+	}
+
+	exists, err := u.repo.StreamExists(ctx, userID, streamID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrChatStreamExpired
+	}
+	return chunks, nil
+}
+
 func (u *mateUseCase) GetUserPages(ctx context.Context, req *models.GetUserPagesRequest) (*models.GetUserPagesResponse, response.ErrorCode, error) {
 	result, err := u.circuitBreaker.Do(ctx, "mate-service.GetUserPages",
 		func(ctx context.Context) (any, error) {
 			return u.mateClient.GetUserPages(ctx, &mateapi.GetUserPagesRequest{
-				UserId:   int32(req.UserID),
-				Cursor:   req.Cursor,
-				PageSize: int32(req.PageSize),
+				UserId:         int32(req.UserID),
+				Cursor:         req.Cursor,
+				PageSize:       int32(req.PageSize),
+				ConversationId: uint32(req.ConversationID),
 			})
 		},
 		func(ctx context.Context, err error) (any, error) {
@@ -131,7 +239,7 @@ func (u *mateUseCase) GetUserPages(ctx context.Context, req *models.GetUserPages
 
 	if err != nil {
 		zap.L().Error("GetUserPages error", zap.Error(err))
-		return nil, response.ServerError, err
+		return nil, response.FromError(err), err
 	}
 
 	switch v := result.(type) {
@@ -139,13 +247,15 @@ func (u *mateUseCase) GetUserPages(ctx context.Context, req *models.GetUserPages
 		pages := make([]models.PageResp, len(v.Pages))
 		for i, page := range v.Pages {
 			pages[i] = models.PageResp{
-				ID:          uint(page.Id),
-				UserID:      uint(page.UserId),
-				SegmentID:   uint(page.SegmentId),
-				UserInput:   page.UserInput,
-				AgentOutput: page.AgentOutput,
-				Status:      page.Status,
-				CreateTime:  page.CreateTime,
+				ID:             uint(page.Id),
+				UserID:         uint(page.UserId),
+				ConversationID: uint(page.ConversationId),
+				SegmentID:      uint(page.SegmentId),
+				UserInput:      page.UserInput,
+				AgentOutput:    page.AgentOutput,
+				Status:         page.Status,
+				Truncated:      page.Truncated,
+				CreateTime:     page.CreateTime,
 			}
 		}
 		return &models.GetUserPagesResponse{
@@ -160,6 +270,62 @@ func (u *mateUseCase) GetUserPages(ctx context.Context, req *models.GetUserPages
 	}
 }
 
+func (u *mateUseCase) GetConversationMessages(ctx context.Context, req *models.GetConversationMessagesReq) ([]models.MessageResp, response.ErrorCode, error) {
+	result, err := u.circuitBreaker.Do(ctx, "mate-service.GetConversationMessages",
+		func(ctx context.Context) (any, error) {
+			return u.mateClient.GetConversationMessages(ctx, &mateapi.GetConversationMessagesRequest{
+				UserId:         int32(req.UserID),
+				ConversationId: uint32(req.ConversationID),
+				StartTime:      req.StartTime,
+				EndTime:        req.EndTime,
+				Limit:          int32(req.Limit),
+			})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("mate GetConversationMessages fallback triggered", zap.Error(err))
+			return []models.MessageResp{}, nil
+		},
+	)
+
+	if err != nil {
+		zap.L().Error("GetConversationMessages error", zap.Error(err))
+		return nil, response.FromError(err), err
+	}
+
+	switch v := result.(type) {
+	case *mateapi.GetConversationMessagesResponse:
+		messages := make([]models.MessageResp, len(v.Messages))
+		for i, message := range v.Messages {
+			messages[i] = models.MessageResp{
+				Role:       message.Role,
+				Content:    message.Content,
+				CreateTime: message.CreateTime,
+			}
+		}
+		return messages, response.NoError, nil
+	case []models.MessageResp:
+		return v, response.DegradedError, nil
+	default:
+		return nil, response.ServerError, fmt.Errorf("unexpected response type")
+	}
+}
+
+func newChatRequest(req *models.ChatReq, userID int) *mateapi.ChatRequest {
+	history := make([]*mateapi.Message, len(req.History))
+	for i, message := range req.History {
+		history[i] = &mateapi.Message{Role: message.Role, Content: message.Content}
+	}
+
+	return &mateapi.ChatRequest{
+		UserId:         int32(userID),
+		Prompt:         req.Prompt,
+		ConversationId: uint32(req.ConversationID),
+		Persona:        req.Persona,
+		Instructions:   req.Instructions,
+		History:        history,
+	}
+}
+
 type MockChatStreamClient struct {
 	content   string
 	messageID string
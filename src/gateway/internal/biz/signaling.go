@@ -3,6 +3,7 @@ package biz
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/models"
 	"github.com/gorilla/websocket"
@@ -16,6 +17,7 @@ type SignalingRepo interface {
 	UnregisterOfferPeer(peerID string) error
 	GetAnswerPeer(peerID string) (*models.Peer, bool)
 	GetOfferPeer(peerID string) (*models.Peer, bool)
+	ListPeers() []*models.Peer
 }
 
 type signalingUseCase struct {
@@ -64,6 +66,18 @@ func (u *signalingUseCase) UnregisterOfferPeer(ctx context.Context, peerID strin
 	return nil
 }
 
+// ClosePeers sends a going-away close frame to every connected peer and
+// closes its socket, which ends the peer's handler and unregisters it.
+func (u *signalingUseCase) ClosePeers(ctx context.Context) {
+	closeMessage := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	for _, peer := range u.repo.ListPeers() {
+		if err := peer.Conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(time.Second)); err != nil {
+			zap.L().Warn("failed to send close frame", zap.String("peer_id", peer.ID), zap.Error(err))
+		}
+		peer.Conn.Close()
+	}
+}
+
 func (u *signalingUseCase) HandleAnswerPeerMessages(ctx context.Context, conn *websocket.Conn, sourcePeerID string) error {
 	for {
 		select {
@@ -0,0 +1,151 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Fl0rencess720/Doria/src/common/metering"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/models"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/response"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type UsageRepo interface {
+	GetDailyUsage(ctx context.Context, userID int, at time.Time) (*metering.Report, error)
+	GetMonthlyUsage(ctx context.Context, userID int, at time.Time) (*metering.Report, error)
+	GetUserPlan(ctx context.Context, userID int) (string, error)
+	// SetUserPlan assigns a plan to the user; an empty name reverts to the
+	// default plan.
+	SetUserPlan(ctx context.Context, userID int, plan string) error
+}
+
+var ErrQuotaExceeded = errors.New("token quota exceeded")
+
+type usageUseCase struct {
+	repo        UsageRepo
+	plans       map[string]*models.QuotaPlan
+	defaultPlan string
+	guestPlan   string
+}
+
+func NewUsageUsecase(repo UsageRepo) UsageUseCase {
+	plans := []*models.QuotaPlan{}
+	if err := viper.UnmarshalKey("quota.plans", &plans); err != nil {
+		zap.L().Error("failed to load quota plans", zap.Error(err))
+	}
+
+	planMap := make(map[string]*models.QuotaPlan, len(plans))
+	for _, plan := range plans {
+		planMap[plan.Name] = plan
+	}
+
+	return &usageUseCase{
+		repo:        repo,
+		plans:       planMap,
+		defaultPlan: viper.GetString("quota.default_plan"),
+		guestPlan:   viper.GetString("quota.guest_plan"),
+	}
+}
+
+func (u *usageUseCase) GetUsage(ctx context.Context, userID int, guest bool) (*models.UsageResp, response.ErrorCode, error) {
+	now := time.Now()
+
+	plan, err := u.userPlan(ctx, userID, guest)
+	if err != nil {
+		zap.L().Error("GetUserPlan error", zap.Error(err))
+		return nil, response.ServerError, err
+	}
+
+	daily, err := u.repo.GetDailyUsage(ctx, userID, now)
+	if err != nil {
+		zap.L().Error("GetDailyUsage error", zap.Error(err))
+		return nil, response.ServerError, err
+	}
+
+	monthly, err := u.repo.GetMonthlyUsage(ctx, userID, now)
+	if err != nil {
+		zap.L().Error("GetMonthlyUsage error", zap.Error(err))
+		return nil, response.ServerError, err
+	}
+
+	return &models.UsageResp{
+		Plan:    plan.Name,
+		Daily:   toUsagePeriodResp(now.Format(time.DateOnly), daily, plan.DailyTokens),
+		Monthly: toUsagePeriodResp(now.Format("2006-01"), monthly, plan.MonthlyTokens),
+	}, response.NoError, nil
+}
+
+func (u *usageUseCase) CheckQuota(ctx context.Context, userID int, guest bool) (response.ErrorCode, error) {
+	now := time.Now()
+
+	plan, err := u.userPlan(ctx, userID, guest)
+	if err != nil {
+		return response.ServerError, err
+	}
+
+	if plan.DailyTokens > 0 {
+		daily, err := u.repo.GetDailyUsage(ctx, userID, now)
+		if err != nil {
+			return response.ServerError, err
+		}
+		if daily.TotalTokens >= plan.DailyTokens {
+			return response.QuotaExceededError, ErrQuotaExceeded
+		}
+	}
+
+	if plan.MonthlyTokens > 0 {
+		monthly, err := u.repo.GetMonthlyUsage(ctx, userID, now)
+		if err != nil {
+			return response.ServerError, err
+		}
+		if monthly.TotalTokens >= plan.MonthlyTokens {
+			return response.QuotaExceededError, ErrQuotaExceeded
+		}
+	}
+
+	return response.NoError, nil
+}
+
+// userPlan resolves the plan assigned to the user, falling back to the
+// default plan. Guests are always on the guest plan when one is configured,
+// so it follows the token and ends with the upgrade. An unknown plan name
+// means no limits apply.
+func (u *usageUseCase) userPlan(ctx context.Context, userID int, guest bool) (*models.QuotaPlan, error) {
+	name := ""
+	if guest {
+		name = u.guestPlan
+	}
+	if name == "" {
+		var err error
+		if name, err = u.repo.GetUserPlan(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	if name == "" {
+		name = u.defaultPlan
+	}
+
+	if plan, ok := u.plans[name]; ok {
+		return plan, nil
+	}
+	return &models.QuotaPlan{Name: name}, nil
+}
+
+func toUsagePeriodResp(period string, report *metering.Report, limit int64) models.UsagePeriodResp {
+	remaining := int64(0)
+	if limit > 0 {
+		remaining = max(limit-report.TotalTokens, 0)
+	}
+
+	return models.UsagePeriodResp{
+		Period:           period,
+		PromptTokens:     report.PromptTokens,
+		CompletionTokens: report.CompletionTokens,
+		TotalTokens:      report.TotalTokens,
+		Limit:            limit,
+		Remaining:        remaining,
+		Nodes:            report.Nodes,
+	}
+}
@@ -2,29 +2,44 @@ package biz
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/models"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/circuitbreaker"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/jwtc"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/response"
 	userapi "github.com/Fl0rencess720/Doria/src/rpc/user"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 type UserRepo interface {
+	CreateSession(ctx context.Context, session *models.Session) error
+	RotateRefreshToken(ctx context.Context, userID int, sessionID, oldTokenID, newTokenID string) error
+	SessionActive(ctx context.Context, userID int, sessionID string) (bool, error)
+	RevokeSession(ctx context.Context, userID int, sessionID string) error
+	RevokeAllSessions(ctx context.Context, userID int) error
 }
 
+var (
+	ErrSessionNotFound    = errors.New("session not found")
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+)
+
 type userUseCase struct {
 	repo           UserRepo
+	usageRepo      UsageRepo
 	userClient     userapi.UserServiceClient
 	circuitBreaker *circuitbreaker.CircuitBreakerManager
 }
 
-func NewUserUsecase(repo UserRepo, userClient userapi.UserServiceClient,
+func NewUserUsecase(repo UserRepo, usageRepo UsageRepo, userClient userapi.UserServiceClient,
 	cbManager *circuitbreaker.CircuitBreakerManager) UserUseCase {
 	return &userUseCase{
 		repo:           repo,
+		usageRepo:      usageRepo,
 		userClient:     userClient,
 		circuitBreaker: cbManager,
 	}
@@ -51,17 +66,12 @@ func (u *userUseCase) Register(ctx context.Context, req *models.UserRegisterReq)
 
 	if err != nil {
 		zap.L().Error("register error", zap.Error(err))
-		return nil, response.ServerError, err
+		return nil, response.FromError(err), err
 	}
 
 	switch v := result.(type) {
 	case *userapi.RegisterResponse:
-		if v.Code != 2000 {
-			zap.L().Error("register failed", zap.Int("code", int(v.Code)))
-			return nil, response.ErrorCode(v.Code), fmt.Errorf("register failed with code %d", v.Code)
-		}
-
-		accessToken, refreshToken, err := jwtc.GenToken(int(v.UserId))
+		accessToken, refreshToken, err := u.issueTokens(ctx, int(v.UserId), req.Device, "")
 		if err != nil {
 			zap.L().Error("generate token error", zap.Error(err))
 			return nil, response.ServerError, err
@@ -85,6 +95,7 @@ func (u *userUseCase) Login(ctx context.Context, req *models.UserLoginReq) (*mod
 			return u.userClient.Login(ctx, &userapi.LoginRequest{
 				Phone:    req.Phone,
 				Password: req.Password,
+				ClientIp: req.ClientIP,
 			})
 		},
 		func(ctx context.Context, err error) (any, error) {
@@ -99,12 +110,12 @@ func (u *userUseCase) Login(ctx context.Context, req *models.UserLoginReq) (*mod
 
 	if err != nil {
 		zap.L().Error("login error", zap.Error(err))
-		return nil, response.ServerError, err
+		return nil, response.FromError(err), err
 	}
 
 	switch v := result.(type) {
 	case *userapi.LoginResponse:
-		accessToken, refreshToken, err := jwtc.GenToken(int(v.UserId))
+		accessToken, refreshToken, err := u.issueTokens(ctx, int(v.UserId), req.Device, "")
 		if err != nil {
 			zap.L().Error("generate token error", zap.Error(err))
 			return nil, response.ServerError, err
@@ -122,14 +133,406 @@ func (u *userUseCase) Login(ctx context.Context, req *models.UserLoginReq) (*mod
 	}
 }
 
+func (u *userUseCase) SendVerificationCode(ctx context.Context, req *models.SendCodeReq, clientIP string) (*models.SendCodeResp, response.ErrorCode, error) {
+	result, err := u.circuitBreaker.Do(ctx, "user-service.SendVerificationCode",
+		func(ctx context.Context) (any, error) {
+			return u.userClient.SendVerificationCode(ctx, &userapi.SendVerificationCodeRequest{
+				Phone:    req.Phone,
+				Purpose:  req.Purpose,
+				ClientIp: clientIP,
+			})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("send verification code fallback triggered", zap.Error(err))
+			return nil, err
+		},
+	)
+
+	if err != nil {
+		zap.L().Error("send verification code error", zap.Error(err))
+		return nil, response.FromError(err), err
+	}
+
+	switch v := result.(type) {
+	case *userapi.SendVerificationCodeResponse:
+		return &models.SendCodeResp{
+			ExpiresIn:   v.ExpiresIn,
+			ResendAfter: v.ResendAfter,
+		}, response.NoError, nil
+	default:
+		return nil, response.ServerError, fmt.Errorf("unexpected response type")
+	}
+}
+
+// ChangePhone moves the account to a new phone number and, as a password
+// change does, signs out every other session.
+func (u *userUseCase) ChangePhone(ctx context.Context, userID int, req *models.ChangePhoneReq) (*models.UserLoginResp, response.ErrorCode, error) {
+	_, err := u.circuitBreaker.Do(ctx, "user-service.ChangePhone",
+		func(ctx context.Context) (any, error) {
+			return u.userClient.ChangePhone(ctx, &userapi.ChangePhoneRequest{
+				UserId:          int32(userID),
+				NewPhone:        req.Phone,
+				Code:            req.Code,
+				CurrentPassword: req.CurrentPassword,
+			})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("change phone fallback triggered", zap.Error(err))
+			return nil, err
+		},
+	)
+
+	if err != nil {
+		zap.L().Error("change phone error", zap.Error(err))
+		return nil, response.FromError(err), err
+	}
+
+	return u.reissueSessions(ctx, userID, req.Device)
+}
+
+func (u *userUseCase) RequestPasswordReset(ctx context.Context, req *models.RequestPasswordResetReq, clientIP string) (*models.SendCodeResp, response.ErrorCode, error) {
+	result, err := u.circuitBreaker.Do(ctx, "user-service.RequestPasswordReset",
+		func(ctx context.Context) (any, error) {
+			return u.userClient.RequestPasswordReset(ctx, &userapi.RequestPasswordResetRequest{
+				Phone:    req.Phone,
+				ClientIp: clientIP,
+			})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("request password reset fallback triggered", zap.Error(err))
+			return nil, err
+		},
+	)
+
+	if err != nil {
+		zap.L().Error("request password reset error", zap.Error(err))
+		return nil, response.FromError(err), err
+	}
+
+	switch v := result.(type) {
+	case *userapi.RequestPasswordResetResponse:
+		return &models.SendCodeResp{
+			ExpiresIn:   v.ExpiresIn,
+			ResendAfter: v.ResendAfter,
+		}, response.NoError, nil
+	default:
+		return nil, response.ServerError, fmt.Errorf("unexpected response type")
+	}
+}
+
+// ResetPassword sets the new password, signs the user out everywhere and
+// signs them in again on the device that did the reset.
+func (u *userUseCase) ResetPassword(ctx context.Context, req *models.ResetPasswordReq) (*models.UserLoginResp, response.ErrorCode, error) {
+	result, err := u.circuitBreaker.Do(ctx, "user-service.ResetPassword",
+		func(ctx context.Context) (any, error) {
+			return u.userClient.ResetPassword(ctx, &userapi.ResetPasswordRequest{
+				Phone:       req.Phone,
+				Code:        req.Code,
+				NewPassword: req.NewPassword,
+			})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("reset password fallback triggered", zap.Error(err))
+			return nil, err
+		},
+	)
+
+	if err != nil {
+		zap.L().Error("reset password error", zap.Error(err))
+		return nil, response.FromError(err), err
+	}
+
+	switch v := result.(type) {
+	case *userapi.ResetPasswordResponse:
+		return u.reissueSessions(ctx, int(v.UserId), req.Device)
+	default:
+		return nil, response.ServerError, fmt.Errorf("unexpected response type")
+	}
+}
+
+func (u *userUseCase) ChangePassword(ctx context.Context, userID int, req *models.ChangePasswordReq) (*models.UserLoginResp, response.ErrorCode, error) {
+	_, err := u.circuitBreaker.Do(ctx, "user-service.ChangePassword",
+		func(ctx context.Context) (any, error) {
+			return u.userClient.ChangePassword(ctx, &userapi.ChangePasswordRequest{
+				UserId:          int32(userID),
+				CurrentPassword: req.CurrentPassword,
+				NewPassword:     req.NewPassword,
+			})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("change password fallback triggered", zap.Error(err))
+			return nil, err
+		},
+	)
+
+	if err != nil {
+		zap.L().Error("change password error", zap.Error(err))
+		return nil, response.FromError(err), err
+	}
+
+	return u.reissueSessions(ctx, userID, req.Device)
+}
+
+// GuestLogin creates a visitor account and signs it in with guest-scoped
+// tokens on the guest quota plan.
+func (u *userUseCase) GuestLogin(ctx context.Context, req *models.GuestLoginReq) (*models.UserLoginResp, response.ErrorCode, error) {
+	result, err := u.circuitBreaker.Do(ctx, "user-service.CreateVisitor",
+		func(ctx context.Context) (any, error) {
+			return u.userClient.CreateVisitor(ctx, &userapi.CreateVisitorRequest{})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("create visitor fallback triggered", zap.Error(err))
+			return nil, err
+		},
+	)
+
+	if err != nil {
+		zap.L().Error("create visitor error", zap.Error(err))
+		return nil, response.FromError(err), err
+	}
+
+	switch v := result.(type) {
+	case *userapi.CreateVisitorResponse:
+		accessToken, refreshToken, err := u.issueTokens(ctx, int(v.UserId), req.Device, jwtc.ScopeGuest)
+		if err != nil {
+			zap.L().Error("generate token error", zap.Error(err))
+			return nil, response.ServerError, err
+		}
+
+		return &models.UserLoginResp{
+			UserID:       v.UserId,
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
+		}, response.NoError, nil
+	default:
+		return nil, response.ServerError, fmt.Errorf("unexpected response type")
+	}
+}
+
+// UpgradeGuest turns the caller's visitor account into a full account. The
+// user ID is kept, so pages and memory stay with it, and the guest sessions
+// are replaced by a full one.
+func (u *userUseCase) UpgradeGuest(ctx context.Context, userID int, req *models.UpgradeGuestReq) (*models.UserLoginResp, response.ErrorCode, error) {
+	_, err := u.circuitBreaker.Do(ctx, "user-service.UpgradeVisitor",
+		func(ctx context.Context) (any, error) {
+			return u.userClient.UpgradeVisitor(ctx, &userapi.UpgradeVisitorRequest{
+				UserId:   int32(userID),
+				Phone:    req.Phone,
+				Code:     req.Code,
+				Password: req.Password,
+			})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("upgrade visitor fallback triggered", zap.Error(err))
+			return nil, err
+		},
+	)
+
+	if err != nil {
+		zap.L().Error("upgrade visitor error", zap.Error(err))
+		return nil, response.FromError(err), err
+	}
+
+	return u.reissueSessions(ctx, userID, req.Device)
+}
+
+func (u *userUseCase) GetProfile(ctx context.Context, userID int) (*models.UserProfile, response.ErrorCode, error) {
+	result, err := u.circuitBreaker.Do(ctx, "user-service.GetProfile",
+		func(ctx context.Context) (any, error) {
+			return u.userClient.GetProfile(ctx, &userapi.GetProfileRequest{
+				UserId: int32(userID),
+			})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("get profile fallback triggered", zap.Error(err))
+			return nil, err
+		},
+	)
+
+	if err != nil {
+		zap.L().Error("get profile error", zap.Error(err))
+		return nil, response.FromError(err), err
+	}
+
+	switch v := result.(type) {
+	case *userapi.GetProfileResponse:
+		return toUserProfile(v.Profile), response.NoError, nil
+	default:
+		return nil, response.ServerError, fmt.Errorf("unexpected response type")
+	}
+}
+
+func (u *userUseCase) UpdateProfile(ctx context.Context, userID int, req *models.UpdateProfileReq) (*models.UserProfile, response.ErrorCode, error) {
+	result, err := u.circuitBreaker.Do(ctx, "user-service.UpdateProfile",
+		func(ctx context.Context) (any, error) {
+			return u.userClient.UpdateProfile(ctx, &userapi.UpdateProfileRequest{
+				UserId:    int32(userID),
+				Nickname:  req.Nickname,
+				AvatarUrl: req.AvatarURL,
+				Timezone:  req.Timezone,
+				Locale:    req.Locale,
+				TtsVoice:  req.TTSVoice,
+				TtsSpeed:  req.TTSSpeed,
+				AddressAs: req.AddressAs,
+			})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("update profile fallback triggered", zap.Error(err))
+			return nil, err
+		},
+	)
+
+	if err != nil {
+		zap.L().Error("update profile error", zap.Error(err))
+		return nil, response.FromError(err), err
+	}
+
+	switch v := result.(type) {
+	case *userapi.UpdateProfileResponse:
+		return toUserProfile(v.Profile), response.NoError, nil
+	default:
+		return nil, response.ServerError, fmt.Errorf("unexpected response type")
+	}
+}
+
+func toUserProfile(profile *userapi.Profile) *models.UserProfile {
+	return &models.UserProfile{
+		Nickname:  profile.GetNickname(),
+		AvatarURL: profile.GetAvatarUrl(),
+		Timezone:  profile.GetTimezone(),
+		Locale:    profile.GetLocale(),
+		TTSVoice:  profile.GetTtsVoice(),
+		TTSSpeed:  profile.GetTtsSpeed(),
+		AddressAs: profile.GetAddressAs(),
+		UpdatedAt: profile.GetUpdatedAt(),
+	}
+}
+
+// reissueSessions revokes every session of the user after a credential
+// change and opens a fresh one for the caller.
+func (u *userUseCase) reissueSessions(ctx context.Context, userID int, device string) (*models.UserLoginResp, response.ErrorCode, error) {
+	if err := u.repo.RevokeAllSessions(ctx, userID); err != nil {
+		zap.L().Error("revoke all sessions error", zap.Error(err))
+		return nil, response.ServerError, err
+	}
+
+	accessToken, refreshToken, err := u.issueTokens(ctx, userID, device, "")
+	if err != nil {
+		zap.L().Error("generate token error", zap.Error(err))
+		return nil, response.ServerError, err
+	}
+
+	return &models.UserLoginResp{
+		UserID:       int32(userID),
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, response.NoError, nil
+}
+
 func (u *userUseCase) Refresh(ctx context.Context, req *models.UserRefreshReq) (*models.UserRefreshResp, response.ErrorCode, error) {
-	accessToken, err := jwtc.RefreshToken(req.AccessToken, req.RefreshToken)
+	claims, err := jwtc.ParseRefreshToken(req.RefreshToken)
 	if err != nil {
 		zap.L().Error("refresh token error", zap.Error(err))
 		return nil, response.RefreshTokenError, err
 	}
 
+	newTokenID := uuid.New().String()
+	if err := u.repo.RotateRefreshToken(ctx, claims.UserID, claims.SessionID, claims.ID, newTokenID); err != nil {
+		switch {
+		case errors.Is(err, ErrRefreshTokenReused):
+			// A rotated token came back: assume it leaked and kill the whole session.
+			zap.L().Warn("refresh token reuse detected, revoking session",
+				zap.Int("userID", claims.UserID), zap.String("sessionID", claims.SessionID))
+			if err := u.repo.RevokeSession(ctx, claims.UserID, claims.SessionID); err != nil {
+				zap.L().Error("revoke session error", zap.Error(err))
+			}
+			return nil, response.RefreshTokenError, err
+		case errors.Is(err, ErrSessionNotFound):
+			return nil, response.RefreshTokenError, err
+		default:
+			zap.L().Error("rotate refresh token error", zap.Error(err))
+			return nil, response.ServerError, err
+		}
+	}
+
+	accessToken, refreshToken, err := jwtc.GenToken(claims.UserID, claims.SessionID, newTokenID, claims.Scope)
+	if err != nil {
+		zap.L().Error("generate token error", zap.Error(err))
+		return nil, response.ServerError, err
+	}
+
 	return &models.UserRefreshResp{
-		AccessToken: accessToken,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
 	}, response.NoError, nil
 }
+
+func (u *userUseCase) Logout(ctx context.Context, userID int, sessionID string) (response.ErrorCode, error) {
+	if err := u.repo.RevokeSession(ctx, userID, sessionID); err != nil {
+		zap.L().Error("revoke session error", zap.Error(err))
+		return response.ServerError, err
+	}
+
+	return response.NoError, nil
+}
+
+func (u *userUseCase) LogoutAll(ctx context.Context, userID int) (response.ErrorCode, error) {
+	if err := u.repo.RevokeAllSessions(ctx, userID); err != nil {
+		zap.L().Error("revoke all sessions error", zap.Error(err))
+		return response.ServerError, err
+	}
+
+	return response.NoError, nil
+}
+
+func (u *userUseCase) GetAccess(ctx context.Context, userID int) (*models.UserAccess, response.ErrorCode, error) {
+	result, err := u.circuitBreaker.Do(ctx, "user-service.GetUserAccess",
+		func(ctx context.Context) (any, error) {
+			return u.userClient.GetUserAccess(ctx, &userapi.GetUserAccessRequest{
+				UserId: int32(userID),
+			})
+		},
+		func(ctx context.Context, err error) (any, error) {
+			zap.L().Error("get user access fallback triggered", zap.Error(err))
+			return nil, err
+		},
+	)
+
+	if err != nil {
+		zap.L().Error("get user access error", zap.Error(err))
+		return nil, response.FromError(err), err
+	}
+
+	switch v := result.(type) {
+	case *userapi.GetUserAccessResponse:
+		return &models.UserAccess{
+			Role:        v.Role,
+			Permissions: v.Permissions,
+			Disabled:    v.Disabled,
+		}, response.NoError, nil
+	default:
+		return nil, response.ServerError, fmt.Errorf("unexpected response type")
+	}
+}
+
+func (u *userUseCase) ValidateSession(ctx context.Context, userID int, sessionID string) (bool, error) {
+	if sessionID == "" {
+		return false, nil
+	}
+	return u.repo.SessionActive(ctx, userID, sessionID)
+}
+
+func (u *userUseCase) issueTokens(ctx context.Context, userID int, device, scope string) (string, string, error) {
+	session := &models.Session{
+		ID:             uuid.New().String(),
+		UserID:         userID,
+		RefreshTokenID: uuid.New().String(),
+		Device:         device,
+		CreatedAt:      time.Now(),
+	}
+	if err := u.repo.CreateSession(ctx, session); err != nil {
+		return "", "", err
+	}
+
+	return jwtc.GenToken(userID, session.ID, session.RefreshTokenID, scope)
+}
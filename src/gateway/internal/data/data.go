@@ -1,6 +1,23 @@
 package data
 
-import "github.com/google/wire"
+import (
+	"github.com/google/wire"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+)
 
 var ProviderSet = wire.NewSet(NewImageRepo, NewUserRepo, NewTTSRepo,
-	NewMateRepo, NewSignalingRepo, NewImageClient, NewUserClient, NewTTSClient, NewMateClient)
+	NewMateRepo, NewSignalingRepo, NewUsageRepo, NewImageClient, NewUserClient, NewTTSClient, NewMateClient, NewMemoryClient, NewRedis, NewHealthRepo, NewHealthChecker)
+
+func NewRedis() *redis.Client {
+	rdb := redis.NewClient(&redis.Options{
+		Addr:         viper.GetString("REDIS_ADDR"),
+		Password:     viper.GetString("REDIS_PASSWORD"),
+		DB:           viper.GetInt("database.redis.db"),
+		DialTimeout:  viper.GetDuration("database.redis.dial_timeout"),
+		WriteTimeout: viper.GetDuration("database.redis.write_timeout"),
+		ReadTimeout:  viper.GetDuration("database.redis.read_timeout"),
+	})
+
+	return rdb
+}
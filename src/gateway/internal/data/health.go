@@ -0,0 +1,76 @@
+package data
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Fl0rencess720/Doria/src/common/health"
+	"github.com/Fl0rencess720/Doria/src/common/registry"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/biz"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/models"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+var downstreamServices = []string{"doria-user", "doria-mate", "doria-memory", "doria-image", "doria-tts"}
+
+type healthRepo struct {
+	clients map[string]healthpb.HealthClient
+}
+
+func NewHealthChecker(rdb *redis.Client) *health.Checker {
+	checker := health.NewChecker()
+	checker.Add("redis", health.Redis(rdb))
+	return checker
+}
+
+// NewHealthRepo dials every backend on its own connection, so health probes
+// bypass the retry and hedging interceptors of the regular clients.
+func NewHealthRepo() biz.HealthRepo {
+	discoveryManager := registry.NewDiscoveryManager()
+
+	clients := make(map[string]healthpb.HealthClient, len(downstreamServices))
+	for _, name := range downstreamServices {
+		conn, err := discoveryManager.CreateGrpcConnection(
+			context.Background(),
+			name,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		if err != nil {
+			zap.L().Panic("new grpc health client failed", zap.String("service", name), zap.Error(err))
+		}
+		clients[name] = healthpb.NewHealthClient(conn)
+	}
+
+	return &healthRepo{clients: clients}
+}
+
+func (r *healthRepo) CheckDownstream(ctx context.Context) []*models.DownstreamStatus {
+	statuses := make([]*models.DownstreamStatus, len(downstreamServices))
+
+	var wg sync.WaitGroup
+	for i, name := range downstreamServices {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			status := &models.DownstreamStatus{Service: name, Status: health.StatusUp}
+			resp, err := r.clients[name].Check(ctx, &healthpb.HealthCheckRequest{})
+			switch {
+			case err != nil:
+				status.Status = health.StatusDown
+				status.Error = err.Error()
+			case resp.Status != healthpb.HealthCheckResponse_SERVING:
+				status.Status = health.StatusDown
+				status.Error = resp.Status.String()
+			}
+			statuses[i] = status
+		}()
+	}
+	wg.Wait()
+
+	return statuses
+}
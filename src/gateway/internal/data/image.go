@@ -5,6 +5,7 @@ import (
 
 	"github.com/Fl0rencess720/Doria/src/common/registry"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/biz"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/resilience"
 	imageapi "github.com/Fl0rencess720/Doria/src/rpc/image"
 	_ "github.com/mbobakov/grpc-consul-resolver" // Keep for backward compatibility
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
@@ -20,7 +21,7 @@ func NewImageRepo() biz.ImageRepo {
 	return &ImageRepo{}
 }
 
-func NewImageClient() imageapi.ImageServiceClient {
+func NewImageClient(policies *resilience.Policies) imageapi.ImageServiceClient {
 	discoveryManager := registry.NewDiscoveryManager()
 
 	conn, err := discoveryManager.CreateGrpcConnection(
@@ -28,6 +29,8 @@ func NewImageClient() imageapi.ImageServiceClient {
 		"doria-image",
 		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(resilience.UnaryClientInterceptor(policies)),
+		grpc.WithChainStreamInterceptor(resilience.StreamClientInterceptor(policies)),
 	)
 	if err != nil {
 		zap.L().Panic("new grpc client failed", zap.Error(err))
@@ -2,10 +2,18 @@ package data
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/Fl0rencess720/Doria/src/common/registry"
+	"github.com/Fl0rencess720/Doria/src/consts"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/biz"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/models"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/resilience"
 	mateapi "github.com/Fl0rencess720/Doria/src/rpc/mate"
+	"github.com/redis/go-redis/v9"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
@@ -13,13 +21,80 @@ import (
 )
 
 type mateRepo struct {
+	redisClient *redis.Client
 }
 
-func NewMateRepo() biz.MateRepo {
-	return &mateRepo{}
+func NewMateRepo(redisClient *redis.Client) biz.MateRepo {
+	return &mateRepo{
+		redisClient: redisClient,
+	}
+}
+
+func getChatStreamKey(userID int, streamID string) string {
+	return fmt.Sprintf("%s:%d:%s", consts.ChatStreamBufferPrefix, userID, streamID)
+}
+
+func (r *mateRepo) AppendStreamChunk(ctx context.Context, userID int, streamID string, chunk *models.ChatStreamChunk) error {
+	data, err := json.Marshal(chunk)
+	if err != nil {
+		return err
+	}
+
+	key := getChatStreamKey(userID, streamID)
+
+	pipe := r.redisClient.Pipeline()
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: key,
+		ID:     fmt.Sprintf("%d-0", chunk.Seq),
+		Values: map[string]any{"data": data},
+	})
+	pipe.Expire(ctx, key, consts.ChatStreamBufferTTL)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (r *mateRepo) ReadStreamChunks(ctx context.Context, userID int, streamID string, afterSeq int64, block time.Duration) ([]*models.ChatStreamChunk, error) {
+	key := getChatStreamKey(userID, streamID)
+
+	streams, err := r.redisClient.XRead(ctx, &redis.XReadArgs{
+		Streams: []string{key, fmt.Sprintf("%d-0", afterSeq)},
+		Block:   block,
+	}).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return []*models.ChatStreamChunk{}, nil
+		}
+		return nil, err
+	}
+
+	chunks := []*models.ChatStreamChunk{}
+	for _, stream := range streams {
+		for _, message := range stream.Messages {
+			data, ok := message.Values["data"].(string)
+			if !ok {
+				continue
+			}
+			chunk := &models.ChatStreamChunk{}
+			if err := json.Unmarshal([]byte(data), chunk); err != nil {
+				zap.L().Error("failed to unmarshal stream chunk", zap.String("id", message.ID), zap.Error(err))
+				continue
+			}
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	return chunks, nil
+}
+
+func (r *mateRepo) StreamExists(ctx context.Context, userID int, streamID string) (bool, error) {
+	n, err := r.redisClient.Exists(ctx, getChatStreamKey(userID, streamID)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
 }
 
-func NewMateClient() mateapi.MateServiceClient {
+func NewMateClient(policies *resilience.Policies) mateapi.MateServiceClient {
 	discoveryManager := registry.NewDiscoveryManager()
 
 	conn, err := discoveryManager.CreateGrpcConnection(
@@ -27,6 +102,8 @@ func NewMateClient() mateapi.MateServiceClient {
 		"doria-mate",
 		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(resilience.UnaryClientInterceptor(policies)),
+		grpc.WithChainStreamInterceptor(resilience.StreamClientInterceptor(policies)),
 	)
 	if err != nil {
 		zap.L().Panic("new grpc client failed", zap.Error(err))
@@ -0,0 +1,32 @@
+package data
+
+import (
+	"context"
+
+	"github.com/Fl0rencess720/Doria/src/common/registry"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/resilience"
+	memoryapi "github.com/Fl0rencess720/Doria/src/rpc/memory"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func NewMemoryClient(policies *resilience.Policies) memoryapi.MemoryServiceClient {
+	discoveryManager := registry.NewDiscoveryManager()
+
+	conn, err := discoveryManager.CreateGrpcConnection(
+		context.Background(),
+		"doria-memory",
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(resilience.UnaryClientInterceptor(policies)),
+		grpc.WithChainStreamInterceptor(resilience.StreamClientInterceptor(policies)),
+	)
+	if err != nil {
+		zap.L().Panic("new grpc client failed", zap.Error(err))
+	}
+
+	client := memoryapi.NewMemoryServiceClient(conn)
+	return client
+}
@@ -82,3 +82,17 @@ func (r *signalingRepo) GetOfferPeer(peerID string) (*models.Peer, bool) {
 	peer, exists := r.offerPeers[peerID]
 	return peer, exists
 }
+
+func (r *signalingRepo) ListPeers() []*models.Peer {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	peers := make([]*models.Peer, 0, len(r.answerPeers)+len(r.offerPeers))
+	for _, peer := range r.answerPeers {
+		peers = append(peers, peer)
+	}
+	for _, peer := range r.offerPeers {
+		peers = append(peers, peer)
+	}
+	return peers
+}
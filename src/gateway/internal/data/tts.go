@@ -7,9 +7,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Fl0rencess720/Doria/src/common/metrics"
 	"github.com/Fl0rencess720/Doria/src/common/registry"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/biz"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/models"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/resilience"
 	ttsapi "github.com/Fl0rencess720/Doria/src/rpc/tts"
 	"github.com/gorilla/websocket"
 	_ "github.com/mbobakov/grpc-consul-resolver"
@@ -47,7 +49,7 @@ func NewTTSRepo() biz.TTSRepo {
 	}
 }
 
-func NewTTSClient() ttsapi.TTSServiceClient {
+func NewTTSClient(policies *resilience.Policies) ttsapi.TTSServiceClient {
 	discoveryManager := registry.NewDiscoveryManager()
 
 	conn, err := discoveryManager.CreateGrpcConnection(
@@ -55,6 +57,8 @@ func NewTTSClient() ttsapi.TTSServiceClient {
 		"doria-tts",
 		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(resilience.UnaryClientInterceptor(policies)),
+		grpc.WithChainStreamInterceptor(resilience.StreamClientInterceptor(policies)),
 	)
 	if err != nil {
 		zap.L().Panic("new grpc client failed", zap.Error(err))
@@ -179,11 +183,13 @@ func (r *TTSRepo) CreateOfferPeerTrack(ctx context.Context, peerID, targetPeerID
 	}
 
 	zap.L().Info("offer: WebRTC connection established successfully")
+	metrics.WebRTCSessions.Inc()
 
 	go func() {
 		<-ctx.Done()
 		zap.L().Info("offer: cleaning up resources due to context cancellation")
 		signalingManager.Cleanup()
+		metrics.WebRTCSessions.Dec()
 	}()
 
 	return track, nil
@@ -0,0 +1,47 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Fl0rencess720/Doria/src/common/metering"
+	"github.com/Fl0rencess720/Doria/src/consts"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/biz"
+	"github.com/redis/go-redis/v9"
+)
+
+type usageRepo struct {
+	redisClient *redis.Client
+}
+
+func NewUsageRepo(redisClient *redis.Client) biz.UsageRepo {
+	return &usageRepo{
+		redisClient: redisClient,
+	}
+}
+
+func (r *usageRepo) GetDailyUsage(ctx context.Context, userID int, at time.Time) (*metering.Report, error) {
+	return metering.Read(ctx, r.redisClient, metering.DailyKey(uint(userID), at))
+}
+
+func (r *usageRepo) GetMonthlyUsage(ctx context.Context, userID int, at time.Time) (*metering.Report, error) {
+	return metering.Read(ctx, r.redisClient, metering.MonthlyKey(uint(userID), at))
+}
+
+func (r *usageRepo) GetUserPlan(ctx context.Context, userID int) (string, error) {
+	plan, err := r.redisClient.Get(ctx, fmt.Sprintf("%s:%d", consts.QuotaPlanPrefix, userID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return plan, err
+}
+
+func (r *usageRepo) SetUserPlan(ctx context.Context, userID int, plan string) error {
+	key := fmt.Sprintf("%s:%d", consts.QuotaPlanPrefix, userID)
+	if plan == "" {
+		return r.redisClient.Del(ctx, key).Err()
+	}
+	return r.redisClient.Set(ctx, key, plan, 0).Err()
+}
@@ -2,25 +2,133 @@ package data
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 
 	"github.com/Fl0rencess720/Doria/src/common/registry"
+	"github.com/Fl0rencess720/Doria/src/consts"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/biz"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/models"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/jwtc"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/resilience"
 	userapi "github.com/Fl0rencess720/Doria/src/rpc/user"
 	_ "github.com/mbobakov/grpc-consul-resolver" // Keep for backward compatibility
+	"github.com/redis/go-redis/v9"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
+// rotateRefreshTokenScript swaps the session's refresh token ID only if the
+// presented one is current. It returns -1 for an unknown session and 0 when
+// an already rotated token is presented again.
+var rotateRefreshTokenScript = redis.NewScript(`
+local current = redis.call("HGET", KEYS[1], "refresh_token_id")
+if not current then
+  return -1
+end
+if current ~= ARGV[1] then
+  return 0
+end
+redis.call("HSET", KEYS[1], "refresh_token_id", ARGV[2])
+redis.call("PEXPIRE", KEYS[1], ARGV[3])
+redis.call("PEXPIRE", KEYS[2], ARGV[3])
+return 1
+`)
+
 type UserRepo struct {
+	redisClient *redis.Client
+}
+
+func NewUserRepo(redisClient *redis.Client) biz.UserRepo {
+	return &UserRepo{
+		redisClient: redisClient,
+	}
+}
+
+func getSessionKey(sessionID string) string {
+	return fmt.Sprintf("%s:%s", consts.SessionPrefix, sessionID)
+}
+
+func getUserSessionsKey(userID int) string {
+	return fmt.Sprintf("%s:%d", consts.UserSessionsPrefix, userID)
 }
 
-func NewUserRepo() biz.UserRepo {
-	return &UserRepo{}
+func (r *UserRepo) CreateSession(ctx context.Context, session *models.Session) error {
+	sessionKey := getSessionKey(session.ID)
+	userSessionsKey := getUserSessionsKey(session.UserID)
+
+	pipe := r.redisClient.TxPipeline()
+	pipe.HSet(ctx, sessionKey, map[string]any{
+		"user_id":          session.UserID,
+		"refresh_token_id": session.RefreshTokenID,
+		"device":           session.Device,
+		"created_at":       session.CreatedAt.Unix(),
+	})
+	pipe.Expire(ctx, sessionKey, jwtc.RefreshTokenTTL)
+	pipe.SAdd(ctx, userSessionsKey, session.ID)
+	pipe.Expire(ctx, userSessionsKey, jwtc.RefreshTokenTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *UserRepo) RotateRefreshToken(ctx context.Context, userID int, sessionID, oldTokenID, newTokenID string) error {
+	result, err := rotateRefreshTokenScript.Run(ctx, r.redisClient,
+		[]string{getSessionKey(sessionID), getUserSessionsKey(userID)},
+		oldTokenID, newTokenID, jwtc.RefreshTokenTTL.Milliseconds()).Int64()
+	if err != nil {
+		return err
+	}
+
+	switch result {
+	case 1:
+		return nil
+	case 0:
+		return biz.ErrRefreshTokenReused
+	default:
+		return biz.ErrSessionNotFound
+	}
+}
+
+func (r *UserRepo) SessionActive(ctx context.Context, userID int, sessionID string) (bool, error) {
+	owner, err := r.redisClient.HGet(ctx, getSessionKey(sessionID), "user_id").Result()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return owner == strconv.Itoa(userID), nil
+}
+
+func (r *UserRepo) RevokeSession(ctx context.Context, userID int, sessionID string) error {
+	pipe := r.redisClient.TxPipeline()
+	pipe.Del(ctx, getSessionKey(sessionID))
+	pipe.SRem(ctx, getUserSessionsKey(userID), sessionID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (r *UserRepo) RevokeAllSessions(ctx context.Context, userID int) error {
+	userSessionsKey := getUserSessionsKey(userID)
+
+	sessionIDs, err := r.redisClient.SMembers(ctx, userSessionsKey).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(sessionIDs)+1)
+	for _, sessionID := range sessionIDs {
+		keys = append(keys, getSessionKey(sessionID))
+	}
+	keys = append(keys, userSessionsKey)
+
+	return r.redisClient.Del(ctx, keys...).Err()
 }
 
-func NewUserClient() userapi.UserServiceClient {
+func NewUserClient(policies *resilience.Policies) userapi.UserServiceClient {
 	discoveryManager := registry.NewDiscoveryManager()
 
 	conn, err := discoveryManager.CreateGrpcConnection(
@@ -28,6 +136,8 @@ func NewUserClient() userapi.UserServiceClient {
 		"doria-user",
 		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(resilience.UnaryClientInterceptor(policies)),
+		grpc.WithChainStreamInterceptor(resilience.StreamClientInterceptor(policies)),
 	)
 	if err != nil {
 		zap.L().Panic("new grpc client failed", zap.Error(err))
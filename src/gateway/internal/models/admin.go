@@ -0,0 +1,89 @@
+package models
+
+type ForceBreakerReq struct {
+	State string `json:"state" binding:"required,oneof=open closed auto"`
+}
+
+type UserAccess struct {
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+	Disabled    bool     `json:"disabled"`
+}
+
+type ListUsersReq struct {
+	Query    string `form:"query"`
+	Page     int    `form:"page"`
+	PageSize int    `form:"page_size"`
+}
+
+type AdminUserResp struct {
+	ID        int32  `json:"id"`
+	Username  string `json:"username"`
+	Phone     string `json:"phone"`
+	Status    string `json:"status"`
+	Role      string `json:"role"`
+	Disabled  bool   `json:"disabled"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+type ListUsersResp struct {
+	Users []AdminUserResp `json:"users"`
+	Total int64           `json:"total"`
+}
+
+type SetUserDisabledReq struct {
+	Disabled *bool `json:"disabled" binding:"required"`
+}
+
+type SetUserRoleReq struct {
+	Role string `json:"role" binding:"required,oneof=user support admin"`
+}
+
+type MemoryPageResp struct {
+	ID             uint32 `json:"id"`
+	ConversationID uint32 `json:"conversation_id"`
+	UserInput      string `json:"user_input"`
+	AgentOutput    string `json:"agent_output"`
+	CreateTime     int64  `json:"create_time"`
+}
+
+type MemorySegmentResp struct {
+	ID        uint32 `json:"id"`
+	Overview  string `json:"overview"`
+	Visit     int32  `json:"visit"`
+	LastVisit int64  `json:"last_visit"`
+	PageCount int32  `json:"page_count"`
+}
+
+type MemoryKnowledgeResp struct {
+	ID      uint32 `json:"id"`
+	Content string `json:"content"`
+}
+
+type MemoryTiersResp struct {
+	ShortTerm []MemoryPageResp      `json:"short_term"`
+	MidTerm   []MemorySegmentResp   `json:"mid_term"`
+	LongTerm  []MemoryKnowledgeResp `json:"long_term"`
+}
+
+type AuditLog struct {
+	ID        int64  `json:"id"`
+	ActorID   int    `json:"actor_id"`
+	Action    string `json:"action"`
+	Target    string `json:"target"`
+	Status    int    `json:"status"`
+	IP        string `json:"ip"`
+	Detail    string `json:"detail"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+type ListAuditLogsReq struct {
+	ActorID  int `form:"actor_id"`
+	Page     int `form:"page"`
+	PageSize int `form:"page_size"`
+}
+
+type ListAuditLogsResp struct {
+	Logs  []AuditLog `json:"logs"`
+	Total int64      `json:"total"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "github.com/Fl0rencess720/Doria/src/common/health"
+
+const HealthStatusDegraded = "degraded"
+
+type DownstreamStatus struct {
+	Service string `json:"service"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+type HealthResp struct {
+	Status     string                `json:"status"`
+	Checks     []*health.CheckResult `json:"checks"`
+	Downstream []*DownstreamStatus   `json:"downstream"`
+}
+
+// Redacted drops error details, which can name hosts and addresses, for
+// callers outside the deployment.
+func (r *HealthResp) Redacted() *HealthResp {
+	redacted := &HealthResp{
+		Status:     r.Status,
+		Checks:     make([]*health.CheckResult, len(r.Checks)),
+		Downstream: make([]*DownstreamStatus, len(r.Downstream)),
+	}
+	for i, check := range r.Checks {
+		c := *check
+		c.Error = ""
+		redacted.Checks[i] = &c
+	}
+	for i, downstream := range r.Downstream {
+		d := *downstream
+		d.Error = ""
+		redacted.Downstream[i] = &d
+	}
+	return redacted
+}
@@ -1,24 +1,39 @@
 package models
 
 type ChatReq struct {
-	Prompt    string `json:"prompt" binding:"required"`
-	SessionID string `json:"session_id"`
+	Prompt         string `json:"prompt" binding:"required"`
+	SessionID      string `json:"session_id"`
+	ConversationID uint   `json:"conversation_id"`
+
+	// Set by the OpenAI compatible endpoint, which carries its own persona,
+	// system instructions and history.
+	Persona      string        `json:"-"`
+	Instructions string        `json:"-"`
+	History      []ChatMessage `json:"-"`
+}
+
+type ChatMessage struct {
+	Role    string
+	Content string
 }
 
 type PageResp struct {
-	ID          uint   `json:"id"`
-	UserID      uint   `json:"user_id"`
-	SegmentID   uint   `json:"segment_id"`
-	UserInput   string `json:"user_input"`
-	AgentOutput string `json:"agent_output"`
-	Status      string `json:"status"`
-	CreateTime  int64  `json:"create_time"`
+	ID             uint   `json:"id"`
+	UserID         uint   `json:"user_id"`
+	ConversationID uint   `json:"conversation_id"`
+	SegmentID      uint   `json:"segment_id"`
+	UserInput      string `json:"user_input"`
+	AgentOutput    string `json:"agent_output"`
+	Status         string `json:"status"`
+	Truncated      bool   `json:"truncated"`
+	CreateTime     int64  `json:"create_time"`
 }
 
 type GetUserPagesRequest struct {
-	UserID   int    `json:"user_id"`
-	Cursor   string `json:"cursor"`
-	PageSize int    `json:"page_size"`
+	UserID         int    `json:"user_id"`
+	ConversationID uint   `json:"conversation_id"`
+	Cursor         string `json:"cursor"`
+	PageSize       int    `json:"page_size"`
 }
 
 type GetUserPagesResponse struct {
@@ -27,7 +42,22 @@ type GetUserPagesResponse struct {
 	HasMore    bool       `json:"has_more"`
 }
 
+type GetConversationMessagesReq struct {
+	UserID         int   `form:"-"`
+	ConversationID uint  `form:"conversation_id"`
+	StartTime      int64 `form:"start_time"`
+	EndTime        int64 `form:"end_time"`
+	Limit          int   `form:"limit"`
+}
+
+type MessageResp struct {
+	Role       string `json:"role"`
+	Content    string `json:"content"`
+	CreateTime int64  `json:"create_time"`
+}
+
 type ChatStreamChunk struct {
+	Seq       int64  `json:"seq"`
 	Type      string `json:"type"`
 	Content   string `json:"content"`
 	MessageID string `json:"message_id"`
@@ -35,3 +65,45 @@ type ChatStreamChunk struct {
 	Finished  bool   `json:"finished,omitempty"`
 	Error     string `json:"error,omitempty"`
 }
+
+type ConversationResp struct {
+	ID         uint   `json:"id"`
+	Title      string `json:"title"`
+	Archived   bool   `json:"archived"`
+	CreateTime int64  `json:"create_time"`
+	UpdateTime int64  `json:"update_time"`
+}
+
+type CreateConversationReq struct {
+	Title string `json:"title"`
+}
+
+type RenameConversationReq struct {
+	Title string `json:"title" binding:"required"`
+}
+
+type ArchiveConversationReq struct {
+	Archived *bool `json:"archived" binding:"required"`
+}
+
+const (
+	FrameUserMessage = "user_message"
+	FrameStop        = "stop"
+	FrameChunk       = "chunk"
+	FrameDone        = "done"
+	FrameError       = "error"
+	FrameReconnect   = "reconnect"
+)
+
+type ChatFrame struct {
+	Type           string `json:"type"`
+	Prompt         string `json:"prompt,omitempty"`
+	ConversationID uint   `json:"conversation_id,omitempty"`
+	SessionID      string `json:"session_id,omitempty"`
+	Content        string `json:"content,omitempty"`
+	MessageID      string `json:"message_id,omitempty"`
+	Timestamp      int64  `json:"timestamp,omitempty"`
+	Truncated      bool   `json:"truncated,omitempty"`
+	Code           int    `json:"code,omitempty"`
+	Message        string `json:"message,omitempty"`
+}
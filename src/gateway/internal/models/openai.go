@@ -0,0 +1,93 @@
+package models
+
+type ChatCompletionMessage struct {
+	Role    string `json:"role"`
+	Content any    `json:"content"`
+}
+
+type ChatCompletionStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type ChatCompletionReq struct {
+	Model          string                       `json:"model" binding:"required"`
+	Messages       []ChatCompletionMessage      `json:"messages" binding:"required,min=1"`
+	Stream         bool                         `json:"stream"`
+	StreamOptions  *ChatCompletionStreamOptions `json:"stream_options,omitempty"`
+	User           string                       `json:"user,omitempty"`
+	ConversationID uint                         `json:"conversation_id,omitempty"`
+}
+
+type ChatCompletionUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+type ChatCompletionRespMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type ChatCompletionChoice struct {
+	Index        int                       `json:"index"`
+	Message      ChatCompletionRespMessage `json:"message"`
+	FinishReason string                    `json:"finish_reason"`
+}
+
+type ChatCompletionResp struct {
+	ID      string                 `json:"id"`
+	Object  string                 `json:"object"`
+	Created int64                  `json:"created"`
+	Model   string                 `json:"model"`
+	Choices []ChatCompletionChoice `json:"choices"`
+	Usage   ChatCompletionUsage    `json:"usage"`
+}
+
+type ChatCompletionDelta struct {
+	Role    string `json:"role,omitempty"`
+	Content string `json:"content,omitempty"`
+}
+
+type ChatCompletionChunkChoice struct {
+	Index        int                 `json:"index"`
+	Delta        ChatCompletionDelta `json:"delta"`
+	FinishReason *string             `json:"finish_reason"`
+}
+
+type ChatCompletionChunk struct {
+	ID      string                      `json:"id"`
+	Object  string                      `json:"object"`
+	Created int64                       `json:"created"`
+	Model   string                      `json:"model"`
+	Choices []ChatCompletionChunkChoice `json:"choices"`
+	Usage   *ChatCompletionUsage        `json:"usage,omitempty"`
+}
+
+type Persona struct {
+	ID          string `json:"id" mapstructure:"id"`
+	Description string `json:"description" mapstructure:"description"`
+}
+
+type ModelResp struct {
+	ID          string `json:"id"`
+	Object      string `json:"object"`
+	Created     int64  `json:"created"`
+	OwnedBy     string `json:"owned_by"`
+	Description string `json:"description,omitempty"`
+}
+
+type ModelListResp struct {
+	Object string      `json:"object"`
+	Data   []ModelResp `json:"data"`
+}
+
+type OpenAIError struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+	Code    string `json:"code,omitempty"`
+}
+
+type OpenAIErrorResp struct {
+	Error OpenAIError `json:"error"`
+}
@@ -0,0 +1,23 @@
+package models
+
+type QuotaPlan struct {
+	Name          string `mapstructure:"name"`
+	DailyTokens   int64  `mapstructure:"daily_tokens"`
+	MonthlyTokens int64  `mapstructure:"monthly_tokens"`
+}
+
+type UsagePeriodResp struct {
+	Period           string           `json:"period"`
+	PromptTokens     int64            `json:"prompt_tokens"`
+	CompletionTokens int64            `json:"completion_tokens"`
+	TotalTokens      int64            `json:"total_tokens"`
+	Limit            int64            `json:"limit"`
+	Remaining        int64            `json:"remaining"`
+	Nodes            map[string]int64 `json:"nodes"`
+}
+
+type UsageResp struct {
+	Plan    string          `json:"plan"`
+	Daily   UsagePeriodResp `json:"daily"`
+	Monthly UsagePeriodResp `json:"monthly"`
+}
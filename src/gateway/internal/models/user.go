@@ -1,21 +1,127 @@
 package models
 
+import "time"
+
 type UserRegisterReq struct {
 	Phone    string `json:"phone" binding:"required"`
 	Code     string `json:"code" binding:"required"`
 	Password string `json:"password" binding:"required"`
+	Device   string `json:"device"`
 }
 
 type UserLoginReq struct {
 	Phone    string `json:"phone" binding:"required"`
 	Password string `json:"password" binding:"required"`
+	Device   string `json:"device"`
+	ClientIP string `json:"-"`
 }
 
 type UserRefreshReq struct {
-	AccessToken  string `json:"access_token" binding:"required"`
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
+type SendCodeReq struct {
+	Phone   string `json:"phone" binding:"required"`
+	Purpose string `json:"purpose" binding:"required,oneof=register reset_password change_phone"`
+}
+
+type SendCodeResp struct {
+	ExpiresIn   int64 `json:"expires_in"`
+	ResendAfter int64 `json:"resend_after"`
+}
+
+type ChangePhoneReq struct {
+	Phone           string `json:"phone" binding:"required"`
+	Code            string `json:"code" binding:"required"`
+	CurrentPassword string `json:"current_password" binding:"required"`
+	Device          string `json:"device"`
+}
+
+type RequestPasswordResetReq struct {
+	Phone string `json:"phone" binding:"required"`
+}
+
+type ResetPasswordReq struct {
+	Phone       string `json:"phone" binding:"required"`
+	Code        string `json:"code" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+	Device      string `json:"device"`
+}
+
+type ChangePasswordReq struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+	Device          string `json:"device"`
+}
+
+type GuestLoginReq struct {
+	Device string `json:"device"`
+}
+
+type UpgradeGuestReq struct {
+	Phone    string `json:"phone" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	Device   string `json:"device"`
+}
+
+type UserProfile struct {
+	Nickname  string `json:"nickname"`
+	AvatarURL string `json:"avatar_url"`
+	Timezone  string `json:"timezone"`
+	Locale    string `json:"locale"`
+	TTSVoice  string `json:"tts_voice"`
+	TTSSpeed  int32  `json:"tts_speed"`
+	AddressAs string `json:"address_as"`
+	UpdatedAt int64  `json:"updated_at,omitempty"`
+}
+
+// UpdateProfileReq is a partial update: omitted fields keep their value.
+type UpdateProfileReq struct {
+	Nickname  *string `json:"nickname"`
+	AvatarURL *string `json:"avatar_url"`
+	Timezone  *string `json:"timezone"`
+	Locale    *string `json:"locale"`
+	TTSVoice  *string `json:"tts_voice"`
+	TTSSpeed  *int32  `json:"tts_speed" binding:"omitempty,min=0,max=100"`
+	AddressAs *string `json:"address_as"`
+}
+
+// DeleteAccountReq carries the password of full accounts. Guests have none
+// and send an empty body.
+type DeleteAccountReq struct {
+	Password string `json:"password"`
+}
+
+// DeleteAccountResp is the only place the status token is shown; the
+// account and its sessions are gone by the time the purge finishes.
+type DeleteAccountResp struct {
+	DeletionID string `json:"deletion_id"`
+	Token      string `json:"token"`
+}
+
+// GetAccountDeletionReq carries the status token in the body, where access
+// logs don't record it.
+type GetAccountDeletionReq struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type AccountDeletionStep struct {
+	Name        string           `json:"name"`
+	Status      string           `json:"status"`
+	Detail      map[string]int64 `json:"detail,omitempty"`
+	CompletedAt int64            `json:"completed_at,omitempty"`
+}
+
+type AccountDeletion struct {
+	ID          string                 `json:"id"`
+	Status      string                 `json:"status"`
+	RequestedAt int64                  `json:"requested_at"`
+	CompletedAt int64                  `json:"completed_at,omitempty"`
+	Steps       []*AccountDeletionStep `json:"steps"`
+	Receipt     string                 `json:"receipt,omitempty"`
+}
+
 type UserRegisterResp struct {
 	UserID       int32  `json:"user_id"`
 	AccessToken  string `json:"access_token"`
@@ -29,5 +135,14 @@ type UserLoginResp struct {
 }
 
 type UserRefreshResp struct {
-	AccessToken string `json:"access_token"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type Session struct {
+	ID             string
+	UserID         int
+	RefreshTokenID string
+	Device         string
+	CreatedAt      time.Time
 }
@@ -0,0 +1,63 @@
+package apispec
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/google/wire"
+	"go.uber.org/zap"
+)
+
+var ProviderSet = wire.NewSet(NewSpec)
+
+//go:embed openapi.yaml
+var document []byte
+
+// Spec is the gateway OpenAPI document together with a router that maps
+// incoming requests to its operations.
+type Spec struct {
+	Doc    *openapi3.T
+	router routers.Router
+	json   []byte
+}
+
+func NewSpec() *Spec {
+	doc, err := openapi3.NewLoader().LoadFromData(document)
+	if err != nil {
+		zap.L().Panic("load openapi spec failed", zap.Error(err))
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		zap.L().Panic("invalid openapi spec", zap.Error(err))
+	}
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		zap.L().Panic("build openapi router failed", zap.Error(err))
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		zap.L().Panic("marshal openapi spec failed", zap.Error(err))
+	}
+
+	return &Spec{
+		Doc:    doc,
+		router: router,
+		json:   data,
+	}
+}
+
+// FindRoute returns the documented operation for req, or routers.ErrPathNotFound
+// and routers.ErrMethodNotAllowed when the request is not described.
+func (s *Spec) FindRoute(req *http.Request) (*routers.Route, map[string]string, error) {
+	return s.router.FindRoute(req)
+}
+
+func (s *Spec) JSON() []byte {
+	return s.json
+}
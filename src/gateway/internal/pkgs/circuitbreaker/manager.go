@@ -2,35 +2,130 @@ package circuitbreaker
 
 import (
 	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/Fl0rencess720/Doria/src/common/rpcerr"
+	"github.com/Fl0rencess720/Doria/src/consts"
+	"github.com/redis/go-redis/v9"
 	"github.com/sony/gobreaker"
+	"github.com/spf13/viper"
 	"go.uber.org/zap"
 )
 
+const (
+	ForceNone   = "auto"
+	ForceOpen   = "open"
+	ForceClosed = "closed"
+)
+
+// forcedReadTimeout bounds the override lookup made on every call, so a slow
+// Redis costs the override rather than the call.
+const forcedReadTimeout = 50 * time.Millisecond
+
+var (
+	ErrUnknownForceState = errors.New("unknown force state")
+	ErrUnknownBreaker    = errors.New("unknown circuit breaker")
+)
+
+type Policy struct {
+	Name         string        `mapstructure:"name" json:"name"`
+	MaxRequests  uint32        `mapstructure:"max_requests" json:"max_requests"`
+	Interval     time.Duration `mapstructure:"interval" json:"interval"`
+	Timeout      time.Duration `mapstructure:"timeout" json:"timeout"`
+	MinRequests  uint32        `mapstructure:"min_requests" json:"min_requests"`
+	FailureRatio float64       `mapstructure:"failure_ratio" json:"failure_ratio"`
+}
+
+type BreakerStatus struct {
+	Name                 string  `json:"name"`
+	State                string  `json:"state"`
+	Forced               string  `json:"forced"`
+	Requests             uint32  `json:"requests"`
+	TotalSuccesses       uint32  `json:"total_successes"`
+	TotalFailures        uint32  `json:"total_failures"`
+	ConsecutiveSuccesses uint32  `json:"consecutive_successes"`
+	ConsecutiveFailures  uint32  `json:"consecutive_failures"`
+	Policy               *Policy `json:"policy"`
+}
+
+type breaker struct {
+	cb     *gobreaker.CircuitBreaker
+	policy *Policy
+}
+
+// CircuitBreakerManager keeps a breaker per key. Counts and automatic state
+// are per instance; forced states live in Redis so an override applies to
+// every gateway instance.
 type CircuitBreakerManager struct {
-	breakers map[string]*gobreaker.CircuitBreaker
+	mu            sync.RWMutex
+	breakers      map[string]*breaker
+	defaultPolicy *Policy
+	policies      []*Policy
+	redisClient   *redis.Client
 }
 
-func NewCircuitBreakerManager() *CircuitBreakerManager {
+func NewCircuitBreakerManager(redisClient *redis.Client) *CircuitBreakerManager {
+	defaultPolicy := &Policy{
+		MaxRequests:  3,
+		Interval:     60 * time.Second,
+		Timeout:      60 * time.Second,
+		MinRequests:  3,
+		FailureRatio: 0.6,
+	}
+	if err := viper.UnmarshalKey("circuitbreaker.default", defaultPolicy); err != nil {
+		zap.L().Error("failed to load default circuit breaker policy", zap.Error(err))
+	}
+
+	policies := []*Policy{}
+	if err := viper.UnmarshalKey("circuitbreaker.policies", &policies); err != nil {
+		zap.L().Error("failed to load circuit breaker policies", zap.Error(err))
+	}
+
 	return &CircuitBreakerManager{
-		breakers: make(map[string]*gobreaker.CircuitBreaker),
+		breakers:      make(map[string]*breaker),
+		defaultPolicy: defaultPolicy,
+		policies:      policies,
+		redisClient:   redisClient,
 	}
 }
 
 func (m *CircuitBreakerManager) GetBreaker(key string) *gobreaker.CircuitBreaker {
-	if breaker, exists := m.breakers[key]; exists {
-		return breaker
+	return m.getBreaker(key).cb
+}
+
+func (m *CircuitBreakerManager) getBreaker(key string) *breaker {
+	m.mu.RLock()
+	b, exists := m.breakers[key]
+	m.mu.RUnlock()
+	if exists {
+		return b
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if b, exists := m.breakers[key]; exists {
+		return b
 	}
 
+	policy := m.policyFor(key)
 	settings := gobreaker.Settings{
 		Name:        key,
-		MaxRequests: 3,
-		Interval:    time.Second * 60,
-		Timeout:     time.Second * 60,
+		MaxRequests: policy.MaxRequests,
+		Interval:    policy.Interval,
+		Timeout:     policy.Timeout,
+		// Business outcomes such as a wrong password or a missing resource
+		// say nothing about the backend's health.
+		IsSuccessful: func(err error) bool {
+			return err == nil || rpcerr.IsClientError(err)
+		},
 		ReadyToTrip: func(counts gobreaker.Counts) bool {
 			failureRatio := float64(counts.TotalFailures) / float64(counts.Requests)
-			return counts.Requests >= 3 && failureRatio >= 0.6
+			return counts.Requests >= policy.MinRequests && failureRatio >= policy.FailureRatio
 		},
 		OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
 			zap.L().Info("CircuitBreaker state changed",
@@ -38,12 +133,76 @@ func (m *CircuitBreakerManager) GetBreaker(key string) *gobreaker.CircuitBreaker
 				zap.String("from", from.String()),
 				zap.String("to", to.String()),
 			)
+			stateTransitions.WithLabelValues(name, from.String(), to.String()).Inc()
+			stateGauge.WithLabelValues(name).Set(float64(to))
 		},
 	}
 
-	breaker := gobreaker.NewCircuitBreaker(settings)
-	m.breakers[key] = breaker
-	return breaker
+	b = &breaker{
+		cb:     gobreaker.NewCircuitBreaker(settings),
+		policy: policy,
+	}
+	stateGauge.WithLabelValues(key).Set(float64(gobreaker.StateClosed))
+
+	m.breakers[key] = b
+	return b
+}
+
+// policyFor returns the first configured policy whose name matches key,
+// either exactly or as a "prefix*" pattern, with unset fields taken from the
+// default policy.
+func (m *CircuitBreakerManager) policyFor(key string) *Policy {
+	policy := *m.defaultPolicy
+	policy.Name = key
+
+	for _, p := range m.policies {
+		if !p.matches(key) {
+			continue
+		}
+
+		policy.Name = p.Name
+		if p.MaxRequests > 0 {
+			policy.MaxRequests = p.MaxRequests
+		}
+		if p.Interval > 0 {
+			policy.Interval = p.Interval
+		}
+		if p.Timeout > 0 {
+			policy.Timeout = p.Timeout
+		}
+		if p.MinRequests > 0 {
+			policy.MinRequests = p.MinRequests
+		}
+		if p.FailureRatio > 0 {
+			policy.FailureRatio = p.FailureRatio
+		}
+		break
+	}
+
+	return &policy
+}
+
+func (p *Policy) matches(key string) bool {
+	prefix, isPattern := strings.CutSuffix(p.Name, "*")
+	return p.Name == key || (isPattern && strings.HasPrefix(key, prefix))
+}
+
+// lookupBreaker returns the breaker for key if it has served a call or a
+// configured policy names it, so a typo cannot create a stray breaker.
+func (m *CircuitBreakerManager) lookupBreaker(key string) (*breaker, bool) {
+	m.mu.RLock()
+	b, exists := m.breakers[key]
+	m.mu.RUnlock()
+	if exists {
+		return b, true
+	}
+
+	for _, p := range m.policies {
+		if p.matches(key) {
+			return m.getBreaker(key), true
+		}
+	}
+	return nil, false
 }
 
 func (m *CircuitBreakerManager) Do(
@@ -52,7 +211,7 @@ func (m *CircuitBreakerManager) Do(
 	fn func(ctx context.Context) (any, error),
 	fallback func(ctx context.Context, err error) (any, error),
 ) (any, error) {
-	breaker := m.GetBreaker(key)
+	b := m.getBreaker(key)
 
 	wrappedFn := func() (any, error) {
 		select {
@@ -63,9 +222,22 @@ func (m *CircuitBreakerManager) Do(
 		return fn(ctx)
 	}
 
-	result, err := breaker.Execute(wrappedFn)
+	var (
+		result any
+		err    error
+	)
+	switch m.forced(ctx, key) {
+	case ForceOpen:
+		err = gobreaker.ErrOpenState
+	case ForceClosed:
+		result, err = wrappedFn()
+	default:
+		result, err = b.cb.Execute(wrappedFn)
+	}
+	requestsTotal.WithLabelValues(key, resultLabel(err)).Inc()
+
 	if err != nil {
-		if fallback != nil {
+		if fallback != nil && !rpcerr.IsClientError(err) {
 			return fallback(ctx, err)
 		}
 		return nil, err
@@ -73,3 +245,113 @@ func (m *CircuitBreakerManager) Do(
 
 	return result, nil
 }
+
+// forced reads the override for key. Without Redis the breaker runs
+// automatically.
+func (m *CircuitBreakerManager) forced(ctx context.Context, key string) string {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), forcedReadTimeout)
+	defer cancel()
+
+	state, err := m.redisClient.HGet(ctx, consts.CircuitBreakerForcedKey, key).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			zap.L().Warn("failed to read circuit breaker override", zap.String("breaker", key), zap.Error(err))
+		}
+		state = ForceNone
+	}
+	forcedGauge.WithLabelValues(key).Set(forcedValue(state))
+	return state
+}
+
+// Force pins a breaker open or closed regardless of its counts, or hands it
+// back to the automatic state machine with ForceNone. The override is shared
+// by every gateway instance. Keys that match no existing breaker or
+// configured policy yield ErrUnknownBreaker.
+func (m *CircuitBreakerManager) Force(ctx context.Context, key, state string) (*BreakerStatus, error) {
+	switch state {
+	case ForceNone, ForceOpen, ForceClosed:
+	default:
+		return nil, ErrUnknownForceState
+	}
+
+	b, ok := m.lookupBreaker(key)
+	if !ok {
+		return nil, ErrUnknownBreaker
+	}
+
+	var err error
+	if state == ForceNone {
+		err = m.redisClient.HDel(ctx, consts.CircuitBreakerForcedKey, key).Err()
+	} else {
+		err = m.redisClient.HSet(ctx, consts.CircuitBreakerForcedKey, key, state).Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+	forcedGauge.WithLabelValues(key).Set(forcedValue(state))
+
+	return b.status(state), nil
+}
+
+func (m *CircuitBreakerManager) List(ctx context.Context) ([]*BreakerStatus, error) {
+	forced, err := m.redisClient.HGetAll(ctx, consts.CircuitBreakerForcedKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	statuses := make([]*BreakerStatus, 0, len(m.breakers))
+	for key, b := range m.breakers {
+		state, ok := forced[key]
+		if !ok {
+			state = ForceNone
+		}
+		statuses = append(statuses, b.status(state))
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses, nil
+}
+
+func (b *breaker) status(forced string) *BreakerStatus {
+	counts := b.cb.Counts()
+	return &BreakerStatus{
+		Name:                 b.cb.Name(),
+		State:                b.cb.State().String(),
+		Forced:               forced,
+		Requests:             counts.Requests,
+		TotalSuccesses:       counts.TotalSuccesses,
+		TotalFailures:        counts.TotalFailures,
+		ConsecutiveSuccesses: counts.ConsecutiveSuccesses,
+		ConsecutiveFailures:  counts.ConsecutiveFailures,
+		Policy:               b.policy,
+	}
+}
+
+func resultLabel(err error) string {
+	switch {
+	case err == nil:
+		return "success"
+	case errors.Is(err, gobreaker.ErrOpenState), errors.Is(err, gobreaker.ErrTooManyRequests):
+		return "rejected"
+	case rpcerr.IsClientError(err):
+		return "client_error"
+	default:
+		return "failure"
+	}
+}
+
+func forcedValue(state string) float64 {
+	switch state {
+	case ForceOpen:
+		return 1
+	case ForceClosed:
+		return -1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,28 @@
+package circuitbreaker
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	stateGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "doria_circuit_breaker_state",
+		Help: "Current circuit breaker state: 0 closed, 1 half-open, 2 open.",
+	}, []string{"name"})
+
+	forcedGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "doria_circuit_breaker_forced",
+		Help: "Manual override: 1 forced open, -1 forced closed, 0 automatic.",
+	}, []string{"name"})
+
+	stateTransitions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "doria_circuit_breaker_transitions_total",
+		Help: "Circuit breaker state transitions.",
+	}, []string{"name", "from", "to"})
+
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "doria_circuit_breaker_requests_total",
+		Help: "Calls through a circuit breaker by result: success, client_error, failure or rejected.",
+	}, []string{"name", "result"})
+)
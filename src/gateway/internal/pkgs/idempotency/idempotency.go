@@ -0,0 +1,103 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Fl0rencess720/Doria/src/consts"
+	"github.com/google/wire"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+)
+
+var ProviderSet = wire.NewSet(NewStore)
+
+const (
+	StatusProcessing = "processing"
+	StatusCompleted  = "completed"
+)
+
+type Record struct {
+	Status      string `json:"status"`
+	Fingerprint string `json:"fingerprint"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+type Store interface {
+	// Begin claims key for a new request. When the key is already taken the
+	// existing record is returned and acquired is false.
+	Begin(ctx context.Context, key, fingerprint string) (existing *Record, acquired bool, err error)
+	Complete(ctx context.Context, key string, record *Record) error
+	Release(ctx context.Context, key string) error
+}
+
+type redisStore struct {
+	client  *redis.Client
+	ttl     time.Duration
+	lockTTL time.Duration
+}
+
+func NewStore(client *redis.Client) Store {
+	ttl := viper.GetDuration("idempotency.ttl")
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	lockTTL := viper.GetDuration("idempotency.lock_ttl")
+	if lockTTL <= 0 {
+		lockTTL = 5 * time.Minute
+	}
+
+	return &redisStore{
+		client:  client,
+		ttl:     ttl,
+		lockTTL: lockTTL,
+	}
+}
+
+func (s *redisStore) Begin(ctx context.Context, key, fingerprint string) (*Record, bool, error) {
+	data, err := json.Marshal(&Record{Status: StatusProcessing, Fingerprint: fingerprint})
+	if err != nil {
+		return nil, false, err
+	}
+
+	redisKey := consts.IdempotencyPrefix + ":" + key
+	ok, err := s.client.SetNX(ctx, redisKey, data, s.lockTTL).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if ok {
+		return nil, true, nil
+	}
+
+	existing, err := s.client.Get(ctx, redisKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// The previous holder released the key in between; let the caller retry.
+		return &Record{Status: StatusProcessing, Fingerprint: fingerprint}, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	record := &Record{}
+	if err := json.Unmarshal(existing, record); err != nil {
+		return nil, false, err
+	}
+	return record, false, nil
+}
+
+func (s *redisStore) Complete(ctx context.Context, key string, record *Record) error {
+	record.Status = StatusCompleted
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, consts.IdempotencyPrefix+":"+key, data, s.ttl).Err()
+}
+
+func (s *redisStore) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, consts.IdempotencyPrefix+":"+key).Err()
+}
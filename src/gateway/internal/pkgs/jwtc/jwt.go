@@ -9,25 +9,43 @@ import (
 	"github.com/spf13/viper"
 )
 
+const (
+	AccessTokenTTL  = 1 * time.Hour
+	RefreshTokenTTL = 7 * 24 * time.Hour
+)
+
+// ScopeGuest marks tokens issued to visitor accounts. Full accounts carry no
+// scope.
+const ScopeGuest = "guest"
+
 type AuthClaims struct {
-	UserID int `json:"user_id"`
+	UserID    int    `json:"user_id"`
+	SessionID string `json:"sid"`
+	Scope     string `json:"scope,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenAccessToken(userID int) (string, error) {
-	accessSecret := viper.GetString("JWT_ACCESS_SECRET")
+type RefreshClaims struct {
+	UserID    int    `json:"user_id"`
+	SessionID string `json:"sid"`
+	Scope     string `json:"scope,omitempty"`
+	jwt.RegisteredClaims
+}
 
+func GenAccessToken(userID int, sessionID, scope string) (string, error) {
 	ac := AuthClaims{
-		UserID: userID,
+		UserID:    userID,
+		SessionID: sessionID,
+		Scope:     scope,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ID:        time.Now().String(),
 			Issuer:    "Fl0rencess720",
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(1 * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(AccessTokenTTL)),
 		},
 	}
 
-	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, ac).SignedString([]byte(accessSecret))
+	accessToken, err := signAccessToken(ac)
 	if err != nil {
 		return "", err
 	}
@@ -35,14 +53,21 @@ func GenAccessToken(userID int) (string, error) {
 	return accessToken, nil
 }
 
-func GenRefreshToken() (string, error) {
+// GenRefreshToken signs a refresh token bound to the user's session. tokenID
+// becomes the jti and must match the one stored for the session on refresh.
+func GenRefreshToken(userID int, sessionID, tokenID, scope string) (string, error) {
 	refreshSecret := viper.GetString("JWT_REFRESH_SECRET")
 
-	rc := jwt.RegisteredClaims{
-		ID:        time.Now().String(),
-		Issuer:    "Fl0rencess720",
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(7 * 24 * time.Hour)),
+	rc := RefreshClaims{
+		UserID:    userID,
+		SessionID: sessionID,
+		Scope:     scope,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Issuer:    "Fl0rencess720",
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(RefreshTokenTTL)),
+		},
 	}
 
 	refreshToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, rc).SignedString([]byte(refreshSecret))
@@ -53,13 +78,13 @@ func GenRefreshToken() (string, error) {
 	return refreshToken, nil
 }
 
-func GenToken(userID int) (string, string, error) {
-	accessToken, err := GenAccessToken(userID)
+func GenToken(userID int, sessionID, refreshTokenID, scope string) (string, string, error) {
+	accessToken, err := GenAccessToken(userID, sessionID, scope)
 	if err != nil {
 		return "", "", err
 	}
 
-	refreshToken, err := GenRefreshToken()
+	refreshToken, err := GenRefreshToken(userID, sessionID, refreshTokenID, scope)
 	if err != nil {
 		return "", "", err
 	}
@@ -68,43 +93,36 @@ func GenToken(userID int) (string, string, error) {
 }
 
 func ParseToken(aToken string) (*AuthClaims, bool, error) {
-	accessSecret := viper.GetString("JWT_ACCESS_SECRET")
-
-	accessToken, err := jwt.ParseWithClaims(aToken, &AuthClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(accessSecret), nil
-	})
+	accessToken, err := jwt.ParseWithClaims(aToken, &AuthClaims{}, accessKeyFunc)
 	if err != nil {
 		return nil, false, err
 	}
 
-	if claims, ok := accessToken.Claims.(*AuthClaims); ok && accessToken.Valid {
+	// Every access token is bound to a session; this also keeps other tokens
+	// signed with the same key, like deletion receipts, out.
+	if claims, ok := accessToken.Claims.(*AuthClaims); ok && accessToken.Valid && claims.SessionID != "" {
 		return claims, false, nil
 	}
 
 	return nil, true, errors.New("invalid token")
 }
 
-func RefreshToken(aToken, rToken string) (string, error) {
-	accessSecret := viper.GetString("JWT_ACCESS_SECRET")
+func ParseRefreshToken(rToken string) (*RefreshClaims, error) {
 	refreshSecret := viper.GetString("JWT_REFRESH_SECRET")
-	var claims AuthClaims
 
 	rToken = strings.TrimPrefix(rToken, "Bearer ")
 
-	_, err := jwt.Parse(rToken, func(token *jwt.Token) (interface{}, error) {
+	refreshToken, err := jwt.ParseWithClaims(rToken, &RefreshClaims{}, func(token *jwt.Token) (interface{}, error) {
 		return []byte(refreshSecret), nil
 	})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	_, err = jwt.ParseWithClaims(aToken, &claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(accessSecret), nil
-	})
-	v, _ := err.(*jwt.ValidationError)
-	if v == nil || v.Errors == jwt.ValidationErrorExpired {
-		return GenAccessToken(claims.UserID)
+	claims, ok := refreshToken.Claims.(*RefreshClaims)
+	if !ok || !refreshToken.Valid || claims.SessionID == "" || claims.ID == "" {
+		return nil, errors.New("invalid refresh token")
 	}
 
-	return "", err
+	return claims, nil
 }
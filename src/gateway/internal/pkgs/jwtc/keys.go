@@ -0,0 +1,243 @@
+package jwtc
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/spf13/viper"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+
+	defaultKeyID = "default"
+)
+
+type KeyConfig struct {
+	Kid            string `mapstructure:"kid"`
+	PrivateKey     string `mapstructure:"private_key"`
+	PrivateKeyFile string `mapstructure:"private_key_file"`
+	PublicKey      string `mapstructure:"public_key"`
+	PublicKeyFile  string `mapstructure:"public_key_file"`
+}
+
+type signingKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private any
+	public  any
+}
+
+type keySet struct {
+	signing *signingKey
+	keys    map[string]*signingKey
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+var keys *keySet
+
+// Init loads the access token keys from the jwt section of the config. With
+// RS256 or EdDSA every configured key is accepted for verification while only
+// jwt.signing_kid is used to sign; keys without a private part are
+// verification-only, which lets retired keys stay valid until their tokens
+// expire.
+func Init() error {
+	algorithm := viper.GetString("jwt.algorithm")
+	if algorithm == "" {
+		algorithm = AlgorithmHS256
+	}
+
+	if algorithm == AlgorithmHS256 {
+		kid := viper.GetString("jwt.signing_kid")
+		if kid == "" {
+			kid = defaultKeyID
+		}
+		secret := []byte(viper.GetString("JWT_ACCESS_SECRET"))
+		key := &signingKey{kid: kid, method: jwt.SigningMethodHS256, private: secret, public: secret}
+		keys = &keySet{signing: key, keys: map[string]*signingKey{kid: key}}
+		return nil
+	}
+
+	var method jwt.SigningMethod
+	switch algorithm {
+	case AlgorithmRS256:
+		method = jwt.SigningMethodRS256
+	case AlgorithmEdDSA:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return fmt.Errorf("unsupported jwt algorithm %q", algorithm)
+	}
+
+	configs := []*KeyConfig{}
+	if err := viper.UnmarshalKey("jwt.keys", &configs); err != nil {
+		return err
+	}
+
+	set := &keySet{keys: make(map[string]*signingKey, len(configs))}
+	for _, cfg := range configs {
+		key, err := loadKey(method, cfg)
+		if err != nil {
+			return fmt.Errorf("load jwt key %q: %w", cfg.Kid, err)
+		}
+		set.keys[key.kid] = key
+	}
+
+	signingKID := viper.GetString("jwt.signing_kid")
+	signing, ok := set.keys[signingKID]
+	if !ok || signing.private == nil {
+		return fmt.Errorf("jwt signing key %q not found or has no private key", signingKID)
+	}
+	set.signing = signing
+
+	keys = set
+	return nil
+}
+
+func loadKey(method jwt.SigningMethod, cfg *KeyConfig) (*signingKey, error) {
+	if cfg.Kid == "" {
+		return nil, errors.New("missing kid")
+	}
+
+	privatePEM, err := readPEM(cfg.PrivateKey, cfg.PrivateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	publicPEM, err := readPEM(cfg.PublicKey, cfg.PublicKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &signingKey{kid: cfg.Kid, method: method}
+	switch method {
+	case jwt.SigningMethodRS256:
+		if privatePEM != nil {
+			private, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+			if err != nil {
+				return nil, err
+			}
+			key.private, key.public = private, &private.PublicKey
+		} else if publicPEM != nil {
+			if key.public, err = jwt.ParseRSAPublicKeyFromPEM(publicPEM); err != nil {
+				return nil, err
+			}
+		}
+	case jwt.SigningMethodEdDSA:
+		if privatePEM != nil {
+			private, err := jwt.ParseEdPrivateKeyFromPEM(privatePEM)
+			if err != nil {
+				return nil, err
+			}
+			key.private, key.public = private, private.(crypto.Signer).Public()
+		} else if publicPEM != nil {
+			if key.public, err = jwt.ParseEdPublicKeyFromPEM(publicPEM); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if key.public == nil {
+		return nil, errors.New("neither private nor public key configured")
+	}
+	return key, nil
+}
+
+func readPEM(inline, file string) ([]byte, error) {
+	if inline != "" {
+		return []byte(inline), nil
+	}
+	if file != "" {
+		return os.ReadFile(file)
+	}
+	return nil, nil
+}
+
+func signAccessToken(claims jwt.Claims) (string, error) {
+	if keys == nil {
+		return "", errors.New("jwt keys not initialized")
+	}
+
+	token := jwt.NewWithClaims(keys.signing.method, claims)
+	token.Header["kid"] = keys.signing.kid
+	return token.SignedString(keys.signing.private)
+}
+
+func accessKeyFunc(token *jwt.Token) (interface{}, error) {
+	if keys == nil {
+		return nil, errors.New("jwt keys not initialized")
+	}
+
+	key := keys.signing
+	if kid, ok := token.Header["kid"].(string); ok {
+		if key, ok = keys.keys[kid]; !ok {
+			return nil, fmt.Errorf("unknown kid %q", kid)
+		}
+	} else if key.method != jwt.SigningMethodHS256 {
+		return nil, errors.New("missing kid")
+	}
+
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+	return key.public, nil
+}
+
+// PublicJWKS returns the verification keys in JWK form. It is empty in HS256
+// mode since the shared secret must not be published.
+func PublicJWKS() *JWKS {
+	jwks := &JWKS{Keys: []JWK{}}
+	if keys == nil {
+		return jwks
+	}
+
+	for _, key := range keys.keys {
+		switch public := key.public.(type) {
+		case *rsa.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				Kty: "RSA",
+				Kid: key.kid,
+				Use: "sig",
+				Alg: key.method.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				Kty: "OKP",
+				Kid: key.kid,
+				Use: "sig",
+				Alg: key.method.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(public),
+			})
+		}
+	}
+
+	sort.Slice(jwks.Keys, func(i, j int) bool {
+		return jwks.Keys[i].Kid < jwks.Keys[j].Kid
+	})
+	return jwks
+}
@@ -0,0 +1,54 @@
+package jwtc
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// TypeDeletionReceipt marks deletion receipts. They carry no session, so the
+// auth middleware never accepts one as an access token.
+const TypeDeletionReceipt = "account_deletion_receipt"
+
+// ErrReceiptUnavailable is returned in HS256 mode: a receipt signed with the
+// shared secret could be forged by anyone holding it and verified by no one
+// else.
+var ErrReceiptUnavailable = errors.New("deletion receipts need an RS256 or EdDSA signing key")
+
+type ReceiptStep struct {
+	Name        string `json:"name"`
+	CompletedAt int64  `json:"completed_at"`
+}
+
+// DeletionReceiptClaims prove that an account was erased: jti is the
+// deletion ID, sub the deleted user ID and iat the completion time.
+type DeletionReceiptClaims struct {
+	Type        string        `json:"typ"`
+	RequestedAt int64         `json:"requested_at"`
+	Steps       []ReceiptStep `json:"steps"`
+	jwt.RegisteredClaims
+}
+
+// GenDeletionReceipt signs a receipt with the access token key, so anyone can
+// verify it against the published JWKS. It never expires.
+func GenDeletionReceipt(deletionID string, userID int, requestedAt, completedAt time.Time, steps []ReceiptStep) (string, error) {
+	if keys != nil && keys.signing.method == jwt.SigningMethodHS256 {
+		return "", ErrReceiptUnavailable
+	}
+
+	rc := DeletionReceiptClaims{
+		Type:        TypeDeletionReceipt,
+		RequestedAt: requestedAt.Unix(),
+		Steps:       steps,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:       deletionID,
+			Subject:  strconv.Itoa(userID),
+			Issuer:   "Fl0rencess720",
+			IssuedAt: jwt.NewNumericDate(completedAt),
+		},
+	}
+
+	return signAccessToken(rc)
+}
@@ -0,0 +1,123 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const memoryCleanupInterval = time.Minute
+
+type memoryEntry struct {
+	tat        time.Time
+	timestamps []time.Time
+	expireAt   time.Time
+}
+
+type memoryLimiter struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+}
+
+func NewMemoryLimiter() Limiter {
+	l := &memoryLimiter{
+		entries: make(map[string]*memoryEntry),
+	}
+	go l.cleanup()
+	return l
+}
+
+func (l *memoryLimiter) Allow(ctx context.Context, key string, rule *Rule) (*Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	entryKey := rule.Name + ":" + key
+	entry, ok := l.entries[entryKey]
+	if !ok {
+		entry = &memoryEntry{}
+		l.entries[entryKey] = entry
+	}
+
+	if rule.Algorithm == AlgorithmSlidingWindow {
+		return l.slidingWindow(entry, rule, now), nil
+	}
+	return l.gcra(entry, rule, now), nil
+}
+
+func (l *memoryLimiter) gcra(entry *memoryEntry, rule *Rule, now time.Time) *Result {
+	emissionInterval := rule.Period / time.Duration(rule.Limit)
+
+	tat := entry.tat
+	if tat.Before(now) {
+		tat = now
+	}
+
+	newTat := tat.Add(emissionInterval)
+	allowAt := newTat.Add(-emissionInterval * time.Duration(rule.Burst))
+	diff := now.Sub(allowAt)
+
+	if diff < 0 {
+		return &Result{
+			Allowed:    false,
+			Limit:      rule.Limit,
+			RetryAfter: -diff,
+			ResetAfter: tat.Sub(now),
+		}
+	}
+
+	entry.tat = newTat
+	entry.expireAt = newTat
+	return &Result{
+		Allowed:    true,
+		Limit:      rule.Limit,
+		Remaining:  int(diff / emissionInterval),
+		ResetAfter: newTat.Sub(now),
+	}
+}
+
+func (l *memoryLimiter) slidingWindow(entry *memoryEntry, rule *Rule, now time.Time) *Result {
+	windowStart := now.Add(-rule.Period)
+
+	kept := entry.timestamps[:0]
+	for _, ts := range entry.timestamps {
+		if ts.After(windowStart) {
+			kept = append(kept, ts)
+		}
+	}
+	entry.timestamps = kept
+
+	if len(entry.timestamps) < rule.Limit {
+		entry.timestamps = append(entry.timestamps, now)
+		entry.expireAt = now.Add(rule.Period)
+		return &Result{
+			Allowed:    true,
+			Limit:      rule.Limit,
+			Remaining:  rule.Limit - len(entry.timestamps),
+			ResetAfter: rule.Period,
+		}
+	}
+
+	retryAfter := entry.timestamps[0].Add(rule.Period).Sub(now)
+	return &Result{
+		Allowed:    false,
+		Limit:      rule.Limit,
+		RetryAfter: retryAfter,
+		ResetAfter: retryAfter,
+	}
+}
+
+func (l *memoryLimiter) cleanup() {
+	ticker := time.NewTicker(memoryCleanupInterval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		l.mu.Lock()
+		for key, entry := range l.entries {
+			if entry.expireAt.Before(now) {
+				delete(l.entries, key)
+			}
+		}
+		l.mu.Unlock()
+	}
+}
@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+var ProviderSet = wire.NewSet(NewLimiter, NewRules)
+
+const (
+	AlgorithmGCRA          = "gcra"
+	AlgorithmSlidingWindow = "sliding_window"
+
+	KeyIP    = "ip"
+	KeyUser  = "user"
+	KeyRoute = "route"
+)
+
+type Rule struct {
+	Name      string        `mapstructure:"name"`
+	Key       string        `mapstructure:"key"`
+	Routes    []string      `mapstructure:"routes"`
+	Algorithm string        `mapstructure:"algorithm"`
+	Limit     int           `mapstructure:"limit"`
+	Period    time.Duration `mapstructure:"period"`
+	Burst     int           `mapstructure:"burst"`
+}
+
+type Rules []*Rule
+
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+	ResetAfter time.Duration
+}
+
+type Limiter interface {
+	Allow(ctx context.Context, key string, rule *Rule) (*Result, error)
+}
+
+func NewRules() Rules {
+	rules := []*Rule{}
+	if err := viper.UnmarshalKey("ratelimit.rules", &rules); err != nil {
+		zap.L().Error("failed to load rate limit rules", zap.Error(err))
+	}
+
+	valid := make(Rules, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Limit <= 0 || rule.Period <= 0 {
+			zap.L().Warn("skip invalid rate limit rule", zap.String("rule", rule.Name))
+			continue
+		}
+		if rule.Burst <= 0 {
+			rule.Burst = rule.Limit
+		}
+		if rule.Algorithm == "" {
+			rule.Algorithm = AlgorithmGCRA
+		}
+		if rule.Key == "" {
+			rule.Key = KeyIP
+		}
+		valid = append(valid, rule)
+	}
+
+	if len(valid) == 0 {
+		valid = append(valid, &Rule{
+			Name:      "default",
+			Key:       KeyIP,
+			Algorithm: AlgorithmGCRA,
+			Limit:     10,
+			Period:    time.Second,
+			Burst:     20,
+		})
+	}
+
+	return valid
+}
+
+func NewLimiter(redisClient *redis.Client) Limiter {
+	memory := NewMemoryLimiter()
+	if viper.GetString("ratelimit.backend") == "memory" {
+		return memory
+	}
+	return &fallbackLimiter{
+		primary:  NewRedisLimiter(redisClient),
+		fallback: memory,
+	}
+}
+
+type fallbackLimiter struct {
+	primary  Limiter
+	fallback Limiter
+}
+
+func (l *fallbackLimiter) Allow(ctx context.Context, key string, rule *Rule) (*Result, error) {
+	result, err := l.primary.Allow(ctx, key, rule)
+	if err == nil {
+		return result, nil
+	}
+
+	zap.L().Warn("distributed rate limiter unavailable, falling back to memory", zap.Error(err))
+	return l.fallback.Allow(ctx, key, rule)
+}
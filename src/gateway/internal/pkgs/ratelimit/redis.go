@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "ratelimit"
+
+var gcraScript = redis.NewScript(`
+local key = KEYS[1]
+local emission_interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+
+local tat = tonumber(redis.call("GET", key))
+if not tat or tat < now then
+  tat = now
+end
+
+local new_tat = tat + emission_interval
+local allow_at = new_tat - emission_interval * burst
+local diff = now - allow_at
+
+if diff < 0 then
+  return {0, 0, -diff, tat - now}
+end
+
+redis.call("SET", key, new_tat, "PX", math.ceil((new_tat - now) / 1000))
+return {1, math.floor(diff / emission_interval), 0, new_tat - now}
+`)
+
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+local member = ARGV[3]
+
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+
+redis.call("ZREMRANGEBYSCORE", key, 0, now - window)
+local count = redis.call("ZCARD", key)
+
+if count < limit then
+  redis.call("ZADD", key, now, member)
+  redis.call("PEXPIRE", key, math.ceil(window / 1000))
+  return {1, limit - count - 1, 0, window}
+end
+
+local oldest = redis.call("ZRANGE", key, 0, 0, "WITHSCORES")
+local retry_after = tonumber(oldest[2]) + window - now
+return {0, 0, retry_after, retry_after}
+`)
+
+type redisLimiter struct {
+	client *redis.Client
+}
+
+func NewRedisLimiter(client *redis.Client) Limiter {
+	return &redisLimiter{client: client}
+}
+
+func (l *redisLimiter) Allow(ctx context.Context, key string, rule *Rule) (*Result, error) {
+	redisKey := fmt.Sprintf("%s:%s:%s", keyPrefix, rule.Name, key)
+
+	var (
+		values []int64
+		err    error
+	)
+	switch rule.Algorithm {
+	case AlgorithmSlidingWindow:
+		values, err = slidingWindowScript.Run(ctx, l.client, []string{redisKey},
+			rule.Period.Microseconds(), rule.Limit, uuid.New().String()).Int64Slice()
+	default:
+		emissionInterval := rule.Period.Microseconds() / int64(rule.Limit)
+		values, err = gcraScript.Run(ctx, l.client, []string{redisKey},
+			emissionInterval, rule.Burst).Int64Slice()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 4 {
+		return nil, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+
+	return &Result{
+		Allowed:    values[0] == 1,
+		Limit:      rule.Limit,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Microsecond,
+		ResetAfter: time.Duration(values[3]) * time.Microsecond,
+	}, nil
+}
@@ -0,0 +1,38 @@
+package resilience
+
+import "sync"
+
+// retryBudget caps retries and hedges to a fraction of the original traffic
+// so that a struggling backend is not hit by a retry storm.
+type retryBudget struct {
+	mu        sync.Mutex
+	ratio     float64
+	maxTokens float64
+	tokens    float64
+}
+
+func newRetryBudget(cfg BudgetConfig) *retryBudget {
+	return &retryBudget{
+		ratio:     cfg.Ratio,
+		maxTokens: cfg.MaxTokens,
+		tokens:    cfg.MaxTokens,
+	}
+}
+
+func (b *retryBudget) deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = min(b.tokens+b.ratio, b.maxTokens)
+}
+
+func (b *retryBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,206 @@
+package resilience
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/Fl0rencess720/Doria/src/common/rpcerr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// UnaryClientInterceptor applies per-method deadlines to every unary call and
+// retries or hedges the ones marked idempotent. Each interceptor owns its own
+// retry budget, so install one per client connection.
+func UnaryClientInterceptor(policies *Policies) grpc.UnaryClientInterceptor {
+	budget := newRetryBudget(policies.budget)
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		policy := policies.For(method)
+
+		if policy.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, policy.Timeout)
+			defer cancel()
+		}
+
+		budget.deposit()
+		if !policy.Idempotent {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		call := &unaryCall{
+			policy:  policy,
+			budget:  budget,
+			method:  method,
+			req:     req,
+			cc:      cc,
+			invoker: invoker,
+			opts:    opts,
+		}
+
+		if msg, ok := reply.(proto.Message); ok && policy.HedgeDelay > 0 && policy.MaxAttempts > 1 {
+			return call.hedge(ctx, msg)
+		}
+		return call.retry(ctx, reply)
+	}
+}
+
+// StreamClientInterceptor only applies deadlines, and only for stream methods
+// that have an explicit timeout configured. Streams are never retried.
+func StreamClientInterceptor(policies *Policies) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		m := policies.match(method)
+		if m == nil || m.Timeout <= 0 {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, m.Timeout)
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		return &timeoutStream{ClientStream: stream, cancel: cancel}, nil
+	}
+}
+
+type timeoutStream struct {
+	grpc.ClientStream
+	cancel context.CancelFunc
+}
+
+func (s *timeoutStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.cancel()
+	}
+	return err
+}
+
+type unaryCall struct {
+	policy  *MethodPolicy
+	budget  *retryBudget
+	method  string
+	req     any
+	cc      *grpc.ClientConn
+	invoker grpc.UnaryInvoker
+	opts    []grpc.CallOption
+}
+
+func (c *unaryCall) attempt(ctx context.Context, reply any) error {
+	if c.policy.AttemptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.policy.AttemptTimeout)
+		defer cancel()
+	}
+	return c.invoker(ctx, c.method, c.req, reply, c.cc, c.opts...)
+}
+
+func (c *unaryCall) retry(ctx context.Context, reply any) error {
+	backoff := c.policy.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := c.attempt(ctx, reply)
+		if err == nil || attempt >= c.policy.MaxAttempts || !c.retryable(ctx, err) || !c.budget.withdraw() {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(max(jitter(backoff), rpcerr.RetryAfterOf(err))):
+		}
+		backoff = min(backoff*2, c.policy.MaxBackoff)
+	}
+}
+
+// hedge starts another attempt every HedgeDelay while earlier ones are still
+// outstanding, and returns the first successful reply.
+func (c *unaryCall) hedge(ctx context.Context, reply proto.Message) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		reply proto.Message
+		err   error
+	}
+	results := make(chan result, c.policy.MaxAttempts)
+
+	launched, inflight := 0, 0
+	launch := func() {
+		launched++
+		inflight++
+		r := reply.ProtoReflect().New().Interface()
+		go func() {
+			results <- result{reply: r, err: c.attempt(ctx, r)}
+		}()
+	}
+
+	launch()
+	timer := time.NewTimer(c.policy.HedgeDelay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			if launched < c.policy.MaxAttempts && c.budget.withdraw() {
+				launch()
+				timer.Reset(c.policy.HedgeDelay)
+			}
+		case res := <-results:
+			inflight--
+			if res.err == nil {
+				proto.Reset(reply)
+				proto.Merge(reply, res.reply)
+				return nil
+			}
+			if !c.retryable(ctx, res.err) {
+				return res.err
+			}
+			if inflight == 0 {
+				if launched < c.policy.MaxAttempts && c.budget.withdraw() {
+					launch()
+					continue
+				}
+				return res.err
+			}
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+// retryable only retries transport failures. A service that answers with a
+// reason has made a decision, e.g. a locked account or an exhausted quota,
+// and repeating the call cannot change it unless the service asks for a
+// retry with a RetryInfo.
+func (c *unaryCall) retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if rpcerr.IsClientError(err) {
+		return false
+	}
+	if rpcerr.HasReason(err) {
+		return rpcerr.RetryAfterOf(err) > 0
+	}
+
+	code := status.Code(err)
+	if code == codes.DeadlineExceeded {
+		// Only a per-attempt timeout is worth retrying; the overall deadline
+		// has been checked above.
+		return c.policy.AttemptTimeout > 0
+	}
+	return retryableCodes[code]
+}
+
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
@@ -0,0 +1,118 @@
+package resilience
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+)
+
+var ProviderSet = wire.NewSet(NewPolicies)
+
+type MethodPolicy struct {
+	// Method is a full gRPC method name such as "/mate.MateService/GetUserPages",
+	// or a "prefix*" pattern.
+	Method         string        `mapstructure:"method"`
+	Timeout        time.Duration `mapstructure:"timeout"`
+	AttemptTimeout time.Duration `mapstructure:"attempt_timeout"`
+	Idempotent     bool          `mapstructure:"idempotent"`
+	MaxAttempts    int           `mapstructure:"max_attempts"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`
+	HedgeDelay     time.Duration `mapstructure:"hedge_delay"`
+}
+
+type BudgetConfig struct {
+	// Ratio is the number of retries earned per original call.
+	Ratio     float64 `mapstructure:"ratio"`
+	MaxTokens float64 `mapstructure:"max_tokens"`
+}
+
+type Policies struct {
+	defaultPolicy *MethodPolicy
+	methods       []*MethodPolicy
+	budget        BudgetConfig
+}
+
+var retryableCodes = map[codes.Code]bool{
+	codes.Unavailable: true,
+	codes.Aborted:     true,
+}
+
+func NewPolicies() *Policies {
+	defaultPolicy := &MethodPolicy{
+		Timeout:        30 * time.Second,
+		MaxAttempts:    3,
+		InitialBackoff: 50 * time.Millisecond,
+		MaxBackoff:     time.Second,
+	}
+	if err := viper.UnmarshalKey("grpcclient.default", defaultPolicy); err != nil {
+		zap.L().Error("failed to load default grpc client policy", zap.Error(err))
+	}
+
+	methods := []*MethodPolicy{}
+	if err := viper.UnmarshalKey("grpcclient.methods", &methods); err != nil {
+		zap.L().Error("failed to load grpc client method policies", zap.Error(err))
+	}
+
+	budget := BudgetConfig{Ratio: 0.1, MaxTokens: 10}
+	if err := viper.UnmarshalKey("grpcclient.retry_budget", &budget); err != nil {
+		zap.L().Error("failed to load grpc client retry budget", zap.Error(err))
+	}
+
+	return &Policies{
+		defaultPolicy: defaultPolicy,
+		methods:       methods,
+		budget:        budget,
+	}
+}
+
+// For merges the first matching method policy over the default one. A
+// method is only retried or hedged when its policy marks it idempotent.
+func (p *Policies) For(method string) *MethodPolicy {
+	policy := *p.defaultPolicy
+	policy.Method = method
+	policy.Idempotent = false
+	policy.HedgeDelay = 0
+
+	if m := p.match(method); m != nil {
+		policy.Idempotent = m.Idempotent
+		policy.HedgeDelay = m.HedgeDelay
+		if m.Timeout > 0 {
+			policy.Timeout = m.Timeout
+		}
+		if m.AttemptTimeout > 0 {
+			policy.AttemptTimeout = m.AttemptTimeout
+		}
+		if m.MaxAttempts > 0 {
+			policy.MaxAttempts = m.MaxAttempts
+		}
+		if m.InitialBackoff > 0 {
+			policy.InitialBackoff = m.InitialBackoff
+		}
+		if m.MaxBackoff > 0 {
+			policy.MaxBackoff = m.MaxBackoff
+		}
+	}
+
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	return &policy
+}
+
+func (p *Policies) match(method string) *MethodPolicy {
+	for _, m := range p.methods {
+		if prefix, ok := strings.CutSuffix(m.Method, "*"); ok {
+			if strings.HasPrefix(method, prefix) {
+				return m
+			}
+		} else if m.Method == method {
+			return m
+		}
+	}
+	return nil
+}
@@ -28,16 +28,34 @@ const (
 
 	RateLimitError
 	DegradedError
+	QuotaExceededError
+	IdempotencyInProgressError
+	IdempotencyKeyMismatchError
+	NotFoundError
+	UpstreamLLMError
+	AccountDisabledError
+	PermissionDeniedError
+	AccountLockedError
+	CredentialsError
 
 	NoError
 )
 
 var HttpCode = map[ErrorCode]int{
-	FormError:      400,
-	ServerError:    500,
-	AuthError:      401,
-	RateLimitError: 429,
-	DegradedError:  503,
+	FormError:          400,
+	ServerError:        500,
+	AuthError:          401,
+	RateLimitError:     429,
+	DegradedError:      503,
+	QuotaExceededError: 429,
+
+	IdempotencyInProgressError:  409,
+	IdempotencyKeyMismatchError: 422,
+	NotFoundError:               404,
+	UpstreamLLMError:            502,
+	AccountDisabledError:        403,
+	PermissionDeniedError:       403,
+	AccountLockedError:          429,
 }
 
 var Message = map[ErrorCode]string{
@@ -50,10 +68,20 @@ var Message = map[ErrorCode]string{
 	UserExistError: "用户已存在",
 	CodeError:      "验证码错误",
 
-	UserNotExistError: "用户不存在",
-	PasswordError:     "密码错误",
-	RateLimitError:    "请求过于频繁",
-	DegradedError:     "服务暂时不可用",
+	UserNotExistError:  "用户不存在",
+	PasswordError:      "密码错误",
+	RateLimitError:     "请求过于频繁",
+	DegradedError:      "服务暂时不可用",
+	QuotaExceededError: "用量已超出配额",
+
+	IdempotencyInProgressError:  "相同请求正在处理中",
+	IdempotencyKeyMismatchError: "幂等键已用于其他请求",
+	NotFoundError:               "资源不存在",
+	UpstreamLLMError:            "模型服务调用失败",
+	AccountDisabledError:        "账号已被禁用",
+	PermissionDeniedError:       "没有权限",
+	AccountLockedError:          "登录失败次数过多，请稍后再试",
+	CredentialsError:            "手机号或密码错误",
 }
 
 func SuccessResponse(c *gin.Context, data any) {
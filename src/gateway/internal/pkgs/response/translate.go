@@ -0,0 +1,53 @@
+package response
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/Fl0rencess720/Doria/src/common/rpcerr"
+	"github.com/gin-gonic/gin"
+	"github.com/sony/gobreaker"
+)
+
+var reasonCodes = map[string]ErrorCode{
+	rpcerr.ReasonValidation:    FormError,
+	rpcerr.ReasonNotFound:      NotFoundError,
+	rpcerr.ReasonUserExists:    UserExistError,
+	rpcerr.ReasonInvalidCode:   CodeError,
+	rpcerr.ReasonUserNotFound:  UserNotExistError,
+	rpcerr.ReasonWrongPassword: PasswordError,
+	rpcerr.ReasonQuotaExceeded: QuotaExceededError,
+	rpcerr.ReasonUpstreamLLM:   UpstreamLLMError,
+	rpcerr.ReasonDegraded:      DegradedError,
+
+	rpcerr.ReasonAccountDisabled:  AccountDisabledError,
+	rpcerr.ReasonPermissionDenied: PermissionDeniedError,
+	rpcerr.ReasonRateLimited:      RateLimitError,
+	rpcerr.ReasonAccountLocked:    AccountLockedError,
+
+	rpcerr.ReasonInvalidCredentials: CredentialsError,
+}
+
+// FromError maps an error returned by a backend call onto the ErrorCode
+// clients see, using the google.rpc.ErrorInfo reason the service attached.
+func FromError(err error) ErrorCode {
+	if err == nil {
+		return NoError
+	}
+	if errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests) {
+		return DegradedError
+	}
+	if code, ok := reasonCodes[rpcerr.ReasonOf(err)]; ok {
+		return code
+	}
+	return ServerError
+}
+
+// SetRetryAfter copies the retry delay a backend attached to err into the
+// Retry-After header.
+func SetRetryAfter(c *gin.Context, err error) {
+	if retryAfter := rpcerr.RetryAfterOf(err); retryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	}
+}
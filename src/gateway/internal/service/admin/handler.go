@@ -0,0 +1,231 @@
+package admin
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/biz"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/models"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/circuitbreaker"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/response"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/middlewares"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type AdminHandler struct {
+	cbManager     *circuitbreaker.CircuitBreakerManager
+	adminUseCase  biz.AdminUseCase
+	mateUseCase   biz.MateUseCase
+	healthUseCase biz.HealthUseCase
+}
+
+func NewAdminHandler(cbManager *circuitbreaker.CircuitBreakerManager, adminUseCase biz.AdminUseCase, mateUseCase biz.MateUseCase, healthUseCase biz.HealthUseCase) *AdminHandler {
+	return &AdminHandler{
+		cbManager:     cbManager,
+		adminUseCase:  adminUseCase,
+		mateUseCase:   mateUseCase,
+		healthUseCase: healthUseCase,
+	}
+}
+
+// GetHealth returns the dependency report with the error details that
+// /readyz leaves out.
+func (h *AdminHandler) GetHealth(c *gin.Context) {
+	response.SuccessResponse(c, h.healthUseCase.Check(c.Request.Context()))
+}
+
+func (h *AdminHandler) ListBreakers(c *gin.Context) {
+	statuses, err := h.cbManager.List(c.Request.Context())
+	if err != nil {
+		zap.L().Error("list circuit breakers error", zap.Error(err))
+		response.ErrorResponse(c, response.ServerError)
+		return
+	}
+
+	response.SuccessResponse(c, statuses)
+}
+
+func (h *AdminHandler) ForceBreaker(c *gin.Context) {
+	req := models.ForceBreakerReq{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		zap.L().Error("request bind error", zap.Error(err))
+		response.ErrorResponse(c, response.FormError)
+		return
+	}
+
+	status, err := h.cbManager.Force(c.Request.Context(), c.Param("name"), req.State)
+	if errors.Is(err, circuitbreaker.ErrUnknownBreaker) {
+		response.ErrorResponse(c, response.NotFoundError)
+		return
+	}
+	if errors.Is(err, circuitbreaker.ErrUnknownForceState) {
+		response.ErrorResponse(c, response.FormError)
+		return
+	}
+	if err != nil {
+		zap.L().Error("force circuit breaker error", zap.Error(err))
+		response.ErrorResponse(c, response.ServerError)
+		return
+	}
+
+	zap.L().Warn("circuit breaker overridden by admin",
+		zap.Int("userID", c.GetInt(string(middlewares.UserIDKey))),
+		zap.String("breaker", status.Name),
+		zap.String("state", req.State))
+
+	response.SuccessResponse(c, status)
+}
+
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	req := models.ListUsersReq{}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		zap.L().Error("request bind error", zap.Error(err))
+		response.ErrorResponse(c, response.FormError)
+		return
+	}
+
+	users, errorCode, err := h.adminUseCase.ListUsers(c.Request.Context(), &req)
+	if err != nil {
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, users)
+}
+
+func (h *AdminHandler) SetUserDisabled(c *gin.Context) {
+	userID, ok := targetUserID(c)
+	if !ok {
+		return
+	}
+
+	req := models.SetUserDisabledReq{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		zap.L().Error("request bind error", zap.Error(err))
+		response.ErrorResponse(c, response.FormError)
+		return
+	}
+
+	errorCode, err := h.adminUseCase.SetUserDisabled(c.Request.Context(), userID, *req.Disabled)
+	if err != nil {
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, nil)
+}
+
+func (h *AdminHandler) SetUserRole(c *gin.Context) {
+	userID, ok := targetUserID(c)
+	if !ok {
+		return
+	}
+
+	req := models.SetUserRoleReq{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		zap.L().Error("request bind error", zap.Error(err))
+		response.ErrorResponse(c, response.FormError)
+		return
+	}
+
+	errorCode, err := h.adminUseCase.SetUserRole(c.Request.Context(), userID, req.Role)
+	if err != nil {
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, nil)
+}
+
+func (h *AdminHandler) GetUserPages(c *gin.Context) {
+	userID, ok := targetUserID(c)
+	if !ok {
+		return
+	}
+
+	req := &models.GetUserPagesRequest{
+		UserID:   userID,
+		Cursor:   c.Query("cursor"),
+		PageSize: 20,
+	}
+
+	if conversationIDStr := c.Query("conversation_id"); conversationIDStr != "" {
+		conversationID, err := strconv.ParseUint(conversationIDStr, 10, 32)
+		if err != nil {
+			response.ErrorResponse(c, response.FormError)
+			return
+		}
+		req.ConversationID = uint(conversationID)
+	}
+
+	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+		if pageSize, err := strconv.Atoi(pageSizeStr); err == nil && pageSize > 0 {
+			req.PageSize = min(pageSize, 100)
+		}
+	}
+
+	pages, errorCode, err := h.mateUseCase.GetUserPages(c.Request.Context(), req)
+	if err != nil {
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, pages)
+}
+
+func (h *AdminHandler) GetMemoryTiers(c *gin.Context) {
+	userID, ok := targetUserID(c)
+	if !ok {
+		return
+	}
+
+	tiers, errorCode, err := h.adminUseCase.GetMemoryTiers(c.Request.Context(), userID)
+	if err != nil {
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, tiers)
+}
+
+func (h *AdminHandler) ReprocessMemory(c *gin.Context) {
+	userID, ok := targetUserID(c)
+	if !ok {
+		return
+	}
+
+	errorCode, err := h.adminUseCase.ReprocessMemory(c.Request.Context(), userID)
+	if err != nil {
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, nil)
+}
+
+func (h *AdminHandler) ListAuditLogs(c *gin.Context) {
+	req := models.ListAuditLogsReq{}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		zap.L().Error("request bind error", zap.Error(err))
+		response.ErrorResponse(c, response.FormError)
+		return
+	}
+
+	logs, errorCode, err := h.adminUseCase.ListAuditLogs(c.Request.Context(), &req)
+	if err != nil {
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, logs)
+}
+
+func targetUserID(c *gin.Context) (int, bool) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || userID <= 0 {
+		response.ErrorResponse(c, response.FormError)
+		return 0, false
+	}
+	return userID, true
+}
@@ -0,0 +1,21 @@
+package admin
+
+import (
+	"github.com/Fl0rencess720/Doria/src/common/rbac"
+	"github.com/gin-gonic/gin"
+)
+
+func InitApi(group *gin.RouterGroup, adminHandler *AdminHandler, permission func(string) gin.HandlerFunc) {
+	group.GET("/health", permission(rbac.PermBreakersManage), adminHandler.GetHealth)
+	group.GET("/breakers", permission(rbac.PermBreakersManage), adminHandler.ListBreakers)
+	group.POST("/breakers/:name/force", permission(rbac.PermBreakersManage), adminHandler.ForceBreaker)
+
+	group.GET("/users", permission(rbac.PermUsersRead), adminHandler.ListUsers)
+	group.POST("/users/:id/disable", permission(rbac.PermUsersWrite), adminHandler.SetUserDisabled)
+	group.PUT("/users/:id/role", permission(rbac.PermUsersWrite), adminHandler.SetUserRole)
+	group.GET("/users/:id/pages", permission(rbac.PermMemoryRead), adminHandler.GetUserPages)
+	group.GET("/users/:id/memory", permission(rbac.PermMemoryRead), adminHandler.GetMemoryTiers)
+	group.POST("/users/:id/memory/reprocess", permission(rbac.PermMemoryReprocess), adminHandler.ReprocessMemory)
+
+	group.GET("/audit-logs", permission(rbac.PermAuditRead), adminHandler.ListAuditLogs)
+}
@@ -0,0 +1,23 @@
+package docs
+
+import (
+	"net/http"
+
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/apispec"
+	"github.com/gin-gonic/gin"
+)
+
+type DocsHandler struct {
+	spec *apispec.Spec
+}
+
+func NewDocsHandler(spec *apispec.Spec) *DocsHandler {
+	return &DocsHandler{
+		spec: spec,
+	}
+}
+
+func (h *DocsHandler) OpenAPI(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.Data(http.StatusOK, "application/json; charset=utf-8", h.spec.JSON())
+}
@@ -0,0 +1,9 @@
+package docs
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func InitApi(group *gin.RouterGroup, docsHandler *DocsHandler) {
+	group.GET("/openapi.json", docsHandler.OpenAPI)
+}
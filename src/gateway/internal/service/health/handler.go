@@ -0,0 +1,36 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/Fl0rencess720/Doria/src/common/health"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/biz"
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	healthUseCase biz.HealthUseCase
+}
+
+func NewHealthHandler(healthUseCase biz.HealthUseCase) *HealthHandler {
+	return &HealthHandler{
+		healthUseCase: healthUseCase,
+	}
+}
+
+// Healthz answers 200 as long as the process can serve HTTP. It checks
+// nothing else, so a slow backend never gets the gateway restarted.
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	c.Status(http.StatusOK)
+}
+
+// Readyz is public, so it reports each check's status without error details.
+// The full report is served to admins.
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	resp := h.healthUseCase.Check(c.Request.Context()).Redacted()
+	if resp.Status == health.StatusDown {
+		c.JSON(http.StatusServiceUnavailable, resp)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,10 @@
+package health
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func InitApi(group *gin.RouterGroup, healthHandler *HealthHandler) {
+	group.GET("/healthz", healthHandler.Healthz)
+	group.GET("/readyz", healthHandler.Readyz)
+}
@@ -1,13 +1,24 @@
 package service
 
 import (
+	"context"
 	"net/http"
 	"time"
 
+	"github.com/Fl0rencess720/Doria/src/common/metrics"
+	"github.com/Fl0rencess720/Doria/src/common/shutdown"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/biz"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/apispec"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/idempotency"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/admin"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/docs"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/health"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/image"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/mate"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/middlewares"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/openai"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/signaling"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/usage"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/user"
 	ginZap "github.com/gin-contrib/zap"
 	"github.com/gin-gonic/gin"
@@ -17,7 +28,7 @@ import (
 )
 
 var ProviderSet = wire.NewSet(NewHTTPServer, NewSignalingServer, user.NewUserHandler,
-	image.NewImageHandler, mate.NewMateHandler, signaling.NewSignalingHandler, middlewares.ProviderSet)
+	image.NewImageHandler, mate.NewMateHandler, signaling.NewSignalingHandler, openai.NewOpenAIHandler, usage.NewUsageHandler, admin.NewAdminHandler, health.NewHealthHandler, docs.NewDocsHandler, middlewares.ProviderSet)
 
 type HTTPServer struct {
 	*http.Server
@@ -27,26 +38,46 @@ type SignalingServer struct {
 	*http.Server
 }
 
-func NewHTTPServer(rateLimiter *middlewares.IPRateLimiter, imageHandler *image.ImageHandler, userHandler *user.UserHandler,
-	mateHandler *mate.MateHandler) *HTTPServer {
+func NewHTTPServer(rateLimiter *middlewares.RateLimiter, imageHandler *image.ImageHandler, userHandler *user.UserHandler,
+	mateHandler *mate.MateHandler, openaiHandler *openai.OpenAIHandler, usageHandler *usage.UsageHandler, adminHandler *admin.AdminHandler, healthHandler *health.HealthHandler, docsHandler *docs.DocsHandler, userUseCase biz.UserUseCase, usageUseCase biz.UsageUseCase, adminUseCase biz.AdminUseCase, idempotencyStore idempotency.Store, spec *apispec.Spec, coordinator *shutdown.Coordinator) *HTTPServer {
 	e := gin.New()
+	trustProxies(e)
 	e.Use(gin.Logger(), gin.Recovery(), ginZap.Ginzap(zap.L(), time.RFC3339, false), ginZap.RecoveryWithZap(zap.L(), false))
 
+	e.Use(metrics.Gin())
 	e.Use(middlewares.Trace())
-	e.Use(middlewares.IPRateLimitMiddleware(rateLimiter))
+	e.Use(middlewares.RateLimitMiddleware(rateLimiter))
+	auth := middlewares.Auth(userUseCase)
+	quota := middlewares.Quota(usageUseCase)
+	idempotent := middlewares.Idempotency(idempotencyStore)
+	validator := middlewares.OpenAPIValidator(spec)
+	drain := middlewares.Drain(coordinator)
+	fullAccount := middlewares.FullAccount()
+	noStore := middlewares.NoStore()
+	permission := func(permission string) gin.HandlerFunc {
+		return middlewares.Permission(userUseCase, permission)
+	}
 
-	app := e.Group("/api", middlewares.Cors(), middlewares.Auth())
+	app := e.Group("/api", middlewares.Cors(), auth, middlewares.RateLimitMiddleware(rateLimiter), validator, idempotent)
 	{
-		image.InitApi(app.Group("/image"), imageHandler)
-		user.InitApi(app.Group("/user"), userHandler)
-		mate.InitApi(app.Group("/mate"), mateHandler)
+		image.InitApi(app.Group("/image", fullAccount), imageHandler)
+		user.InitApi(app.Group("/user"), userHandler, fullAccount, noStore)
+		mate.InitApi(app.Group("/mate"), mateHandler, quota, drain)
+		usage.InitApi(app.Group("/usage"), usageHandler)
+		admin.InitApi(app.Group("/admin", fullAccount, middlewares.Audit(adminUseCase)), adminHandler, permission)
 	}
 
-	appNoneAuth := e.Group("/api", middlewares.Cors())
+	appNoneAuth := e.Group("/api", middlewares.Cors(), validator)
 	{
-		user.InitNoneAuthApi(appNoneAuth.Group("/user"), userHandler)
+		user.InitNoneAuthApi(appNoneAuth.Group("/user"), userHandler, noStore)
+		docs.InitApi(appNoneAuth, docsHandler)
 	}
 
+	user.InitWellKnownApi(e.Group("/.well-known", middlewares.Cors()), userHandler)
+	health.InitApi(e.Group(""), healthHandler)
+
+	openai.InitApi(e.Group("/v1", middlewares.Cors(), auth, fullAccount, middlewares.RateLimitMiddleware(rateLimiter), idempotent), openaiHandler, quota)
+
 	return &HTTPServer{
 		Server: &http.Server{
 			Addr:    viper.GetString("server.http.addr"),
@@ -55,8 +86,15 @@ func NewHTTPServer(rateLimiter *middlewares.IPRateLimiter, imageHandler *image.I
 	}
 }
 
-func NewSignalingServer(signalingHandler *signaling.SignalingHandler) *SignalingServer {
+func NewSignalingServer(signalingHandler *signaling.SignalingHandler, signalingUseCase biz.SignalingUseCase, coordinator *shutdown.Coordinator) *SignalingServer {
+	// Peers are closed only after draining, since TTS pipelines still
+	// negotiate through them while they finish.
+	coordinator.RegisterOnDrained(func() {
+		signalingUseCase.ClosePeers(context.Background())
+	})
+
 	e := gin.New()
+	trustProxies(e)
 	e.Use(gin.Logger(), gin.Recovery(), ginZap.Ginzap(zap.L(), time.RFC3339, false), ginZap.RecoveryWithZap(zap.L(), false))
 
 	appNoneAuth := e.Group("/api", middlewares.Cors())
@@ -71,3 +109,15 @@ func NewSignalingServer(signalingHandler *signaling.SignalingHandler) *Signaling
 		},
 	}
 }
+
+// trustProxies decides where c.ClientIP() comes from. Rate limits, login
+// lockouts and code throttles key on it, so X-Forwarded-For and X-Real-IP
+// are only honoured from the proxies in server.http.trusted_proxies. When
+// server.http.trusted_platform names a header such as CF-Connecting-IP, that
+// header wins. With neither set the peer address is used.
+func trustProxies(e *gin.Engine) {
+	e.TrustedPlatform = viper.GetString("server.http.trusted_platform")
+	if err := e.SetTrustedProxies(viper.GetStringSlice("server.http.trusted_proxies")); err != nil {
+		zap.L().Panic("invalid trusted proxies", zap.Error(err))
+	}
+}
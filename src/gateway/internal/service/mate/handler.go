@@ -1,12 +1,14 @@
 package mate
 
 import (
-	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"strconv"
-	"sync"
+	"strings"
 
+	"github.com/Fl0rencess720/Doria/src/common/shutdown"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/biz"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/models"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/response"
@@ -17,15 +19,24 @@ import (
 	"go.uber.org/zap"
 )
 
+// reconnectRetryMillis is the SSE retry hint sent with the reconnect event.
+const reconnectRetryMillis = 1000
+
 type MateHandler struct {
-	mateUseCase biz.MateUseCase
-	ttsUseCase  biz.TTSUseCase
+	mateUseCase  biz.MateUseCase
+	ttsUseCase   biz.TTSUseCase
+	usageUseCase biz.UsageUseCase
+	rateLimiter  *middlewares.RateLimiter
+	shutdown     *shutdown.Coordinator
 }
 
-func NewMateHandler(mateUseCase biz.MateUseCase, ttsUseCase biz.TTSUseCase) *MateHandler {
+func NewMateHandler(mateUseCase biz.MateUseCase, ttsUseCase biz.TTSUseCase, usageUseCase biz.UsageUseCase, rateLimiter *middlewares.RateLimiter, coordinator *shutdown.Coordinator) *MateHandler {
 	return &MateHandler{
-		mateUseCase: mateUseCase,
-		ttsUseCase:  ttsUseCase,
+		mateUseCase:  mateUseCase,
+		ttsUseCase:   ttsUseCase,
+		usageUseCase: usageUseCase,
+		rateLimiter:  rateLimiter,
+		shutdown:     coordinator,
 	}
 }
 
@@ -41,14 +52,14 @@ func (u *MateHandler) Chat(c *gin.Context) {
 		return
 	}
 
-	output, errorCode, err := u.mateUseCase.Chat(ctx, req, userID)
+	resp, errorCode, err := u.mateUseCase.Chat(ctx, req, userID)
 	if err != nil {
 		zap.L().Error("chat error", zap.Error(err))
 		response.ErrorResponse(c, errorCode)
 		return
 	}
 
-	response.SuccessResponse(c, output)
+	response.SuccessResponse(c, resp.Message)
 }
 
 func (u *MateHandler) ChatStream(c *gin.Context) {
@@ -66,40 +77,70 @@ func (u *MateHandler) ChatStream(c *gin.Context) {
 	}
 
 	userID := c.GetInt(string(middlewares.UserIDKey))
-	req := &models.ChatReq{}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		zap.L().Warn("request bind error", zap.Error(err))
-		response.SendSSEError(c.Writer, flusher, "FormError", err.Error())
-		return
+	var (
+		streamID  string
+		lastSeq   int64
+		pw        *io.PipeWriter
+		handedOff bool
+	)
+
+	// Browsers send Last-Event-ID themselves when an EventSource reconnects;
+	// the query parameter lets one open a resumed stream in the first place.
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
 	}
 
-	stream, err := u.mateUseCase.CreateChatStream(ctx, req, userID)
-	if err != nil {
-		zap.L().Error("create chat stream error", zap.Error(err))
-		response.SendSSEError(c.Writer, flusher, "ServerError", err.Error())
+	if lastEventID != "" {
+		var err error
+		streamID, lastSeq, err = parseEventID(lastEventID)
+		if err != nil {
+			zap.L().Warn("invalid Last-Event-ID", zap.String("last_event_id", lastEventID), zap.Error(err))
+			response.SendSSEError(c.Writer, flusher, "FormError", err.Error())
+			return
+		}
+	} else if c.Request.Method == http.MethodGet {
+		response.SendSSEError(c.Writer, flusher, "FormError", "Last-Event-ID is required to resume a stream")
 		return
-	}
-
-	pr, pw := io.Pipe()
+	} else {
+		req := &models.ChatReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			zap.L().Warn("request bind error", zap.Error(err))
+			response.SendSSEError(c.Writer, flusher, "FormError", err.Error())
+			return
+		}
 
-	var wg sync.WaitGroup
-	ttsCtx, ttsCancel := context.WithCancel(context.Background())
+		var err error
+		streamID, err = u.mateUseCase.StartChatStream(ctx, req, userID)
+		if err != nil {
+			zap.L().Error("create chat stream error", zap.Error(err))
+			response.SendSSEError(c.Writer, flusher, "ServerError", err.Error())
+			return
+		}
 
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		if err := u.ttsUseCase.SynthesizeSpeech(ttsCtx, pr, req.SessionID); err != nil {
-			zap.L().Error("tts error", zap.Error(err))
+		if err := sse.Encode(c.Writer, sse.Event{
+			Id:    formatEventID(streamID, 0),
+			Event: "start",
+			Data:  map[string]interface{}{"type": "start", "stream_id": streamID},
+		}); err != nil {
+			zap.L().Error("Error writing to SSE stream (client disconnected?)", zap.Error(err))
 			return
 		}
-	}()
+		flusher.Flush()
 
-	defer func() {
-		pw.Close()
-		wg.Wait()
-		ttsCancel()
-	}()
+		var speechDone <-chan struct{}
+		pw, speechDone = u.startSpeech(req.SessionID)
+		if pw != nil {
+			defer func() {
+				if handedOff {
+					return
+				}
+				pw.Close()
+				<-speechDone
+			}()
+		}
+	}
 
 	for {
 		select {
@@ -109,50 +150,96 @@ func (u *MateHandler) ChatStream(c *gin.Context) {
 		default:
 		}
 
-		resp, err := stream.Recv()
-		if err == io.EOF {
-			zap.L().Info("gRPC stream finished successfully")
-			return
-		}
+		// A draining gateway tells the client to reconnect, which resumes the
+		// buffered stream on another instance, while the speech for this reply
+		// keeps being fed in the background.
+		if u.shutdown.IsDraining() {
+			if err := sse.Encode(c.Writer, sse.Event{
+				Id:    formatEventID(streamID, lastSeq),
+				Event: "reconnect",
+				Retry: reconnectRetryMillis,
+				Data:  map[string]interface{}{"type": "reconnect", "stream_id": streamID},
+			}); err != nil {
+				zap.L().Error("Error writing to SSE stream (client disconnected?)", zap.Error(err))
+			}
+			flusher.Flush()
 
-		if err != nil {
-			zap.L().Error("failed to receive from gRPC stream", zap.Error(err))
-			response.SendSSEError(c.Writer, flusher, "gRPCError", err.Error())
+			if pw != nil {
+				handedOff = true
+				go u.feedSpeech(pw, userID, streamID, lastSeq)
+			}
 			return
 		}
 
-		_, err = pw.Write([]byte(resp.Content))
+		chunks, err := u.mateUseCase.ReadChatStream(ctx, userID, streamID, lastSeq)
 		if err != nil {
-			zap.L().Error("failed to write to pipe", zap.Error(err))
+			if ctx.Err() != nil {
+				return
+			}
+			zap.L().Error("failed to read chat stream", zap.String("stream_id", streamID), zap.Error(err))
 			response.SendSSEError(c.Writer, flusher, "ServerError", err.Error())
 			return
 		}
 
-		data := map[string]interface{}{
-			"type":       "chunk",
-			"content":    resp.Content,
-			"message_id": resp.MessageId,
-			"timestamp":  resp.Timestamp,
-			"finished":   resp.Finished,
-		}
+		for _, chunk := range chunks {
+			lastSeq = chunk.Seq
 
-		if err = sse.Encode(c.Writer, sse.Event{
-			Event: "message",
-			Data:  data,
-		}); err != nil {
-			zap.L().Error("Error writing to SSE stream (client disconnected?)", zap.Error(err))
-			return
-		}
+			if chunk.Type == "error" {
+				response.SendSSEError(c.Writer, flusher, "gRPCError", chunk.Error)
+				return
+			}
 
-		flusher.Flush()
+			if pw != nil {
+				if _, err := pw.Write([]byte(chunk.Content)); err != nil {
+					zap.L().Error("failed to write to pipe", zap.Error(err))
+					response.SendSSEError(c.Writer, flusher, "ServerError", err.Error())
+					return
+				}
+			}
 
-		if resp.Finished {
-			zap.L().Info("Chat response finished")
-			return
+			data := map[string]interface{}{
+				"type":       "chunk",
+				"content":    chunk.Content,
+				"message_id": chunk.MessageID,
+				"timestamp":  chunk.Timestamp,
+				"finished":   chunk.Finished,
+			}
+
+			if err = sse.Encode(c.Writer, sse.Event{
+				Id:    formatEventID(streamID, chunk.Seq),
+				Event: "message",
+				Data:  data,
+			}); err != nil {
+				zap.L().Error("Error writing to SSE stream (client disconnected?)", zap.Error(err))
+				return
+			}
+
+			flusher.Flush()
+
+			if chunk.Finished {
+				zap.L().Info("Chat response finished")
+				return
+			}
 		}
 	}
 }
 
+func formatEventID(streamID string, seq int64) string {
+	return fmt.Sprintf("%s:%d", streamID, seq)
+}
+
+func parseEventID(eventID string) (string, int64, error) {
+	idx := strings.LastIndex(eventID, ":")
+	if idx <= 0 {
+		return "", 0, fmt.Errorf("malformed event id")
+	}
+	seq, err := strconv.ParseInt(eventID[idx+1:], 10, 64)
+	if err != nil || seq < 0 {
+		return "", 0, fmt.Errorf("malformed event id")
+	}
+	return eventID[:idx], seq, nil
+}
+
 func (u *MateHandler) GetUserPages(c *gin.Context) {
 	ctx := c.Request.Context()
 
@@ -164,6 +251,15 @@ func (u *MateHandler) GetUserPages(c *gin.Context) {
 		PageSize: 20,
 	}
 
+	if conversationIDStr := c.Query("conversation_id"); conversationIDStr != "" {
+		conversationID, err := strconv.ParseUint(conversationIDStr, 10, 32)
+		if err != nil {
+			response.ErrorResponse(c, response.FormError)
+			return
+		}
+		req.ConversationID = uint(conversationID)
+	}
+
 	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
 		if pageSize, err := strconv.Atoi(pageSizeStr); err == nil && pageSize > 0 {
 			req.PageSize = pageSize
@@ -182,3 +278,145 @@ func (u *MateHandler) GetUserPages(c *gin.Context) {
 
 	response.SuccessResponse(c, pages)
 }
+
+func (u *MateHandler) GetConversationMessages(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	req := &models.GetConversationMessagesReq{}
+	if err := c.ShouldBindQuery(req); err != nil {
+		zap.L().Warn("request bind error", zap.Error(err))
+		response.ErrorResponse(c, response.FormError)
+		return
+	}
+	req.UserID = c.GetInt(string(middlewares.UserIDKey))
+
+	if req.StartTime > 0 && req.EndTime > 0 && req.StartTime > req.EndTime {
+		response.ErrorResponse(c, response.FormError)
+		return
+	}
+
+	messages, errorCode, err := u.mateUseCase.GetConversationMessages(ctx, req)
+	if err != nil {
+		zap.L().Error("GetConversationMessages error", zap.Error(err))
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, messages)
+}
+
+func (u *MateHandler) CreateConversation(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID := c.GetInt(string(middlewares.UserIDKey))
+
+	// Every field is optional, so an empty body is a valid request.
+	req := &models.CreateConversationReq{}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		zap.L().Warn("request bind error", zap.Error(err))
+		response.ErrorResponse(c, response.FormError)
+		return
+	}
+
+	conversation, errorCode, err := u.mateUseCase.CreateConversation(ctx, req, userID)
+	if err != nil {
+		zap.L().Error("CreateConversation error", zap.Error(err))
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, conversation)
+}
+
+func (u *MateHandler) ListConversations(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID := c.GetInt(string(middlewares.UserIDKey))
+	includeArchived, _ := strconv.ParseBool(c.Query("include_archived"))
+
+	conversations, errorCode, err := u.mateUseCase.ListConversations(ctx, userID, includeArchived)
+	if err != nil {
+		zap.L().Error("ListConversations error", zap.Error(err))
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, conversations)
+}
+
+func (u *MateHandler) RenameConversation(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID := c.GetInt(string(middlewares.UserIDKey))
+
+	conversationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorResponse(c, response.FormError)
+		return
+	}
+
+	req := &models.RenameConversationReq{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		zap.L().Warn("request bind error", zap.Error(err))
+		response.ErrorResponse(c, response.FormError)
+		return
+	}
+
+	conversation, errorCode, err := u.mateUseCase.RenameConversation(ctx, req, userID, uint(conversationID))
+	if err != nil {
+		zap.L().Error("RenameConversation error", zap.Error(err))
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, conversation)
+}
+
+func (u *MateHandler) ArchiveConversation(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID := c.GetInt(string(middlewares.UserIDKey))
+
+	conversationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorResponse(c, response.FormError)
+		return
+	}
+
+	req := &models.ArchiveConversationReq{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		zap.L().Warn("request bind error", zap.Error(err))
+		response.ErrorResponse(c, response.FormError)
+		return
+	}
+
+	conversation, errorCode, err := u.mateUseCase.ArchiveConversation(ctx, req, userID, uint(conversationID))
+	if err != nil {
+		zap.L().Error("ArchiveConversation error", zap.Error(err))
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, conversation)
+}
+
+func (u *MateHandler) DeleteConversation(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID := c.GetInt(string(middlewares.UserIDKey))
+
+	conversationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorResponse(c, response.FormError)
+		return
+	}
+
+	errorCode, err := u.mateUseCase.DeleteConversation(ctx, userID, uint(conversationID))
+	if err != nil {
+		zap.L().Error("DeleteConversation error", zap.Error(err))
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, nil)
+}
@@ -4,9 +4,16 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func InitApi(group *gin.RouterGroup, mateHandler *MateHandler) {
-	group.POST("/send", mateHandler.Chat)
-	group.POST("/stream", mateHandler.ChatStream)
+func InitApi(group *gin.RouterGroup, mateHandler *MateHandler, quota, drain gin.HandlerFunc) {
+	group.POST("/send", quota, mateHandler.Chat)
+	group.POST("/stream", drain, quota, mateHandler.ChatStream)
+	group.GET("/stream", drain, mateHandler.ChatStream)
+	group.GET("/ws", drain, quota, mateHandler.ChatWebSocket)
 	group.GET("/pages", mateHandler.GetUserPages)
-	// group.GET("/messages", mu.GetConversationMessages)
+	group.POST("/conversations", mateHandler.CreateConversation)
+	group.GET("/conversations", mateHandler.ListConversations)
+	group.PATCH("/conversations/:id", mateHandler.RenameConversation)
+	group.POST("/conversations/:id/archive", mateHandler.ArchiveConversation)
+	group.DELETE("/conversations/:id", mateHandler.DeleteConversation)
+	group.GET("/messages", mateHandler.GetConversationMessages)
 }
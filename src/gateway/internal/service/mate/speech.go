@@ -0,0 +1,63 @@
+package mate
+
+import (
+	"context"
+	"io"
+
+	"go.uber.org/zap"
+)
+
+// startSpeech runs the TTS pipeline for one reply and returns the writer that
+// feeds it, or nil when the gateway is draining. The pipeline is tracked by
+// the shutdown coordinator on its own, so it may outlive the request that
+// started it up to the grace period. finished is closed once it has ended.
+func (u *MateHandler) startSpeech(sessionID string) (pw *io.PipeWriter, finished <-chan struct{}) {
+	done, ok := u.shutdown.Track()
+	if !ok {
+		return nil, nil
+	}
+
+	pr, pw := io.Pipe()
+	ch := make(chan struct{})
+	ctx, cancel := context.WithCancel(u.shutdown.Context())
+
+	go func() {
+		defer close(ch)
+		defer done()
+		defer cancel()
+
+		if err := u.ttsUseCase.SynthesizeSpeech(ctx, pr, sessionID); err != nil {
+			zap.L().Error("tts error", zap.Error(err))
+		}
+		pr.Close()
+	}()
+
+	return pw, ch
+}
+
+// feedSpeech keeps feeding a TTS pipeline from the buffered chat stream after
+// its client has been told to reconnect, so the reply is still spoken in full.
+func (u *MateHandler) feedSpeech(pw *io.PipeWriter, userID int, streamID string, afterSeq int64) {
+	defer pw.Close()
+
+	ctx := u.shutdown.Context()
+	for ctx.Err() == nil {
+		chunks, err := u.mateUseCase.ReadChatStream(ctx, userID, streamID, afterSeq)
+		if err != nil {
+			return
+		}
+
+		for _, chunk := range chunks {
+			afterSeq = chunk.Seq
+			if chunk.Type == "error" {
+				return
+			}
+			if _, err := pw.Write([]byte(chunk.Content)); err != nil {
+				return
+			}
+			if chunk.Finished {
+				return
+			}
+		}
+	}
+}
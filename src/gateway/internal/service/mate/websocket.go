@@ -0,0 +1,256 @@
+package mate
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/models"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/response"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/middlewares"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+var upgrader = websocket.Upgrader{
+	Subprotocols: []string{middlewares.WebSocketProtocol},
+	CheckOrigin:  middlewares.CheckWebSocketOrigin,
+}
+
+type chatConn struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+
+	genMu  sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (cc *chatConn) writeFrame(frame *models.ChatFrame) error {
+	cc.writeMu.Lock()
+	defer cc.writeMu.Unlock()
+	return cc.conn.WriteJSON(frame)
+}
+
+func (cc *chatConn) writeError(errorCode response.ErrorCode, message string) {
+	if message == "" {
+		message = response.Message[errorCode]
+	}
+	if err := cc.writeFrame(&models.ChatFrame{
+		Type:      models.FrameError,
+		Code:      int(errorCode),
+		Message:   message,
+		Timestamp: time.Now().Unix(),
+	}); err != nil {
+		zap.L().Error("failed to write websocket error frame", zap.Error(err))
+	}
+}
+
+// waitGeneration blocks until the running generation, if any, has finished
+// or ctx is done.
+func (cc *chatConn) waitGeneration(ctx context.Context) {
+	cc.genMu.Lock()
+	done := cc.done
+	cc.genMu.Unlock()
+
+	if done == nil {
+		return
+	}
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+func (cc *chatConn) stopGeneration() {
+	cc.genMu.Lock()
+	cancel, done := cc.cancel, cc.done
+	cc.cancel, cc.done = nil, nil
+	cc.genMu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+func (u *MateHandler) ChatWebSocket(c *gin.Context) {
+	userID := c.GetInt(string(middlewares.UserIDKey))
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		zap.L().Error("websocket upgrade error", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	connCtx, connCancel := context.WithCancel(c.Request.Context())
+	defer connCancel()
+
+	cc := &chatConn{conn: conn}
+	defer cc.stopGeneration()
+
+	go u.closeOnDrain(connCtx, cc)
+
+	for {
+		frame := &models.ChatFrame{}
+		if err := conn.ReadJSON(frame); err != nil {
+			if !u.shutdown.IsDraining() && !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				zap.L().Warn("websocket read error", zap.Error(err))
+			}
+			return
+		}
+
+		switch frame.Type {
+		case models.FrameUserMessage:
+			if frame.Prompt == "" {
+				cc.writeError(response.FormError, "")
+				continue
+			}
+			if u.shutdown.IsDraining() {
+				cc.writeError(response.DegradedError, "")
+				continue
+			}
+			if errorCode := u.checkMessage(c); errorCode != response.NoError {
+				cc.writeError(errorCode, "")
+				continue
+			}
+
+			cc.stopGeneration()
+
+			genCtx, genCancel := context.WithCancel(connCtx)
+			done := make(chan struct{})
+
+			cc.genMu.Lock()
+			cc.cancel, cc.done = genCancel, done
+			cc.genMu.Unlock()
+
+			go func() {
+				defer close(done)
+				defer genCancel()
+				u.generate(genCtx, cc, userID, frame)
+			}()
+		case models.FrameStop:
+			cc.stopGeneration()
+		default:
+			cc.writeError(response.FormError, "unknown frame type: "+frame.Type)
+		}
+	}
+}
+
+// closeOnDrain lets the running generation finish once the gateway starts
+// draining, then asks the client to reconnect and closes the socket.
+func (u *MateHandler) closeOnDrain(ctx context.Context, cc *chatConn) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-u.shutdown.Draining():
+	}
+
+	cc.waitGeneration(u.shutdown.Context())
+
+	if err := cc.writeFrame(&models.ChatFrame{
+		Type:      models.FrameReconnect,
+		Timestamp: time.Now().Unix(),
+	}); err != nil {
+		zap.L().Warn("failed to write websocket reconnect frame", zap.Error(err))
+	}
+	closeMessage := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	if err := cc.conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(time.Second)); err != nil {
+		zap.L().Warn("failed to write websocket close frame", zap.Error(err))
+	}
+	cc.conn.Close()
+}
+
+// checkMessage applies the rate limits and the token quota to each message,
+// since the middlewares only run on the upgrade request.
+func (u *MateHandler) checkMessage(c *gin.Context) response.ErrorCode {
+	if _, ok := u.rateLimiter.Allow(c); !ok {
+		return response.RateLimitError
+	}
+	return middlewares.CheckQuota(c, u.usageUseCase)
+}
+
+func (u *MateHandler) generate(ctx context.Context, cc *chatConn, userID int, frame *models.ChatFrame) {
+	req := &models.ChatReq{
+		Prompt:         frame.Prompt,
+		SessionID:      frame.SessionID,
+		ConversationID: frame.ConversationID,
+	}
+
+	stream, err := u.mateUseCase.CreateChatStream(ctx, req, userID)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		zap.L().Error("create chat stream error", zap.Error(err))
+		cc.writeError(response.FromError(err), err.Error())
+		return
+	}
+
+	var pw *io.PipeWriter
+	if req.SessionID != "" {
+		var speechDone <-chan struct{}
+		if pw, speechDone = u.startSpeech(req.SessionID); pw != nil {
+			defer func() {
+				pw.Close()
+				<-speechDone
+			}()
+		}
+	}
+
+	var messageID string
+	for {
+		resp, err := stream.Recv()
+		if ctx.Err() != nil {
+			if err := cc.writeFrame(&models.ChatFrame{
+				Type:      models.FrameDone,
+				MessageID: messageID,
+				Timestamp: time.Now().Unix(),
+				Truncated: true,
+			}); err != nil {
+				zap.L().Error("failed to write websocket done frame", zap.Error(err))
+			}
+			return
+		}
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			zap.L().Error("failed to receive from gRPC stream", zap.Error(err))
+			cc.writeError(response.FromError(err), err.Error())
+			return
+		}
+
+		messageID = resp.MessageId
+
+		if resp.Finished {
+			if err := cc.writeFrame(&models.ChatFrame{
+				Type:      models.FrameDone,
+				MessageID: resp.MessageId,
+				Timestamp: resp.Timestamp,
+			}); err != nil {
+				zap.L().Error("failed to write websocket done frame", zap.Error(err))
+			}
+			return
+		}
+
+		if pw != nil {
+			if _, err := pw.Write([]byte(resp.Content)); err != nil {
+				zap.L().Error("failed to write to pipe", zap.Error(err))
+			}
+		}
+
+		if err := cc.writeFrame(&models.ChatFrame{
+			Type:      models.FrameChunk,
+			Content:   resp.Content,
+			MessageID: resp.MessageId,
+			Timestamp: resp.Timestamp,
+		}); err != nil {
+			zap.L().Error("failed to write websocket chunk frame", zap.Error(err))
+			return
+		}
+	}
+}
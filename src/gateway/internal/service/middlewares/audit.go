@@ -0,0 +1,61 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/biz"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/models"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const maxAuditDetail = 1024
+
+// Audit records every request in the group to the audit log once it has been
+// handled, including ones Permission rejected. It must run after Auth and
+// before Permission.
+func Audit(adminUseCase biz.AdminUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var detail []byte
+		if c.Request.Body != nil {
+			body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxAuditDetail+1))
+			if err != nil {
+				zap.L().Error("read audit body error", zap.Error(err))
+			}
+			c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+			detail = body[:min(len(body), maxAuditDetail)]
+		}
+
+		c.Next()
+
+		target := c.Param("id")
+		if target == "" {
+			target = c.Param("name")
+		}
+		log := &models.AuditLog{
+			ActorID: c.GetInt(string(UserIDKey)),
+			Action:  c.Request.Method + " " + c.FullPath(),
+			Target:  target,
+			Status:  c.Writer.Status(),
+			IP:      c.ClientIP(),
+			Detail:  string(detail),
+		}
+		if c.Request.URL.RawQuery != "" {
+			log.Detail = c.Request.URL.RawQuery + " " + log.Detail
+		}
+
+		zap.L().Info("admin action",
+			zap.Int("actorID", log.ActorID),
+			zap.String("action", log.Action),
+			zap.String("target", log.Target),
+			zap.Int("status", log.Status))
+
+		// The request context may already be canceled by a client that hung
+		// up, but the action happened and must still be recorded.
+		if err := adminUseCase.RecordAudit(context.WithoutCancel(c.Request.Context()), log); err != nil {
+			zap.L().Error("record audit log error", zap.String("action", log.Action), zap.Error(err))
+		}
+	}
+}
@@ -1,22 +1,38 @@
 package middlewares
 
 import (
+	"net/http"
 	"strings"
 
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/biz"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/jwtc"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/response"
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
 )
 
 type ContextKey string
 
 var (
-	UserIDKey = ContextKey("user_id")
+	UserIDKey    = ContextKey("user_id")
+	SessionIDKey = ContextKey("session_id")
+	ScopeKey     = ContextKey("scope")
 )
 
-func Auth() gin.HandlerFunc {
+// WebSocketProtocol is the subprotocol browsers offer alongside their access
+// token, since they cannot set the Authorization header on an upgrade and a
+// query parameter would end up in the request logs.
+const WebSocketProtocol = "doria.v1"
+
+func Auth(userUseCase biz.UserUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tokenString := c.GetHeader("Authorization")
+		if tokenString == "" && websocket.IsWebSocketUpgrade(c.Request) {
+			if token := webSocketToken(c.Request); token != "" {
+				tokenString = "Bearer " + token
+			}
+		}
 		if tokenString == "" {
 			response.AuthErrorResponse(c, response.AuthError)
 			return
@@ -38,7 +54,30 @@ func Auth() gin.HandlerFunc {
 			return
 		}
 
+		active, err := userUseCase.ValidateSession(c.Request.Context(), parsedToken.UserID, parsedToken.SessionID)
+		if err != nil {
+			zap.L().Error("validate session error", zap.Error(err))
+			response.AuthErrorResponse(c, response.ServerError)
+			return
+		}
+		if !active {
+			response.AuthErrorResponse(c, response.AuthError)
+			return
+		}
+
 		c.Set(string(UserIDKey), parsedToken.UserID)
+		c.Set(string(SessionIDKey), parsedToken.SessionID)
+		c.Set(string(ScopeKey), parsedToken.Scope)
 		c.Next()
 	}
 }
+
+// webSocketToken returns the access token offered as the second entry of
+// Sec-WebSocket-Protocol, as in "doria.v1, <token>".
+func webSocketToken(r *http.Request) string {
+	protocols := websocket.Subprotocols(r)
+	if len(protocols) != 2 || protocols[0] != WebSocketProtocol {
+		return ""
+	}
+	return protocols[1]
+}
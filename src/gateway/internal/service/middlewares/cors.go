@@ -1,17 +1,36 @@
 package middlewares
 
 import (
+	"net/http"
+	"slices"
+
 	"github.com/gin-contrib/cors"
+	"github.com/spf13/viper"
 
 	"github.com/gin-gonic/gin"
 )
 
 func Cors() gin.HandlerFunc {
 	return cors.New(cors.Config{
-		AllowOrigins:     []string{"*"},
+		AllowOriginFunc:  AllowOrigin,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
 		ExposeHeaders:    []string{"Content-Length", "Access-Control-Allow-Origin", "Access-Control-Allow-Headers", "Content-Type"},
 		AllowCredentials: true,
 	})
 }
+
+// AllowOrigin reports whether origin is on the server.http.cors.allow_origins
+// list, where "*" allows any origin.
+func AllowOrigin(origin string) bool {
+	origins := viper.GetStringSlice("server.http.cors.allow_origins")
+	return slices.Contains(origins, "*") || slices.Contains(origins, origin)
+}
+
+// CheckWebSocketOrigin applies the CORS allow-list to WebSocket upgrades,
+// which browsers never preflight. Clients that send no Origin are not
+// browsers and are let through.
+func CheckWebSocketOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	return origin == "" || AllowOrigin(origin)
+}
@@ -0,0 +1,25 @@
+package middlewares
+
+import (
+	"github.com/Fl0rencess720/Doria/src/common/shutdown"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/response"
+	"github.com/gin-gonic/gin"
+)
+
+// Drain tracks long-lived requests such as SSE streams and WebSockets so a
+// shutdown waits for them, and turns new ones away with DegradedError once
+// draining has started so clients retry against another instance.
+func Drain(coordinator *shutdown.Coordinator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		done, ok := coordinator.Track()
+		if !ok {
+			c.Header("Retry-After", "1")
+			response.ErrorResponse(c, response.DegradedError)
+			c.Abort()
+			return
+		}
+		defer done()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,25 @@
+package middlewares
+
+import (
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/jwtc"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/response"
+	"github.com/gin-gonic/gin"
+)
+
+// IsGuest reports whether the request was authenticated with a guest token.
+func IsGuest(c *gin.Context) bool {
+	return c.GetString(string(ScopeKey)) == jwtc.ScopeGuest
+}
+
+// FullAccount keeps guest tokens away from routes that need a verified
+// account. It must run after Auth.
+func FullAccount() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if IsGuest(c) {
+			response.AuthErrorResponse(c, response.PermissionDeniedError)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,168 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/idempotency"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/response"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	idempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	maxIdempotentResponseSize = 1 << 20
+)
+
+type responseRecorder struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.record(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.record([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *responseRecorder) record(data []byte) {
+	if w.overflow {
+		return
+	}
+	if w.body.Len()+len(data) > maxIdempotentResponseSize {
+		w.overflow = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(data)
+}
+
+// NoStore marks responses that carry credentials. Clients and proxies must
+// not cache them and Idempotency never keeps them for replay.
+func NoStore() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "no-store")
+		c.Next()
+	}
+}
+
+// Idempotency replays the stored response when a mutating request is sent
+// again with the same Idempotency-Key. Keys are scoped to the user and to the
+// method, path and query string; unauthenticated requests are passed through
+// untouched, since there is no owner to scope them to. Server errors, 429s,
+// streamed responses and responses marked by NoStore are not stored, so
+// those requests can be retried with the same key.
+func Idempotency(store idempotency.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || !isMutating(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if _, exists := c.Get(string(UserIDKey)); !exists {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			response.ErrorResponse(c, response.FormError)
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			response.ErrorResponse(c, response.FormError)
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		scopedKey := idempotencyScope(c, key)
+		fingerprint := requestFingerprint(c, body)
+
+		existing, acquired, err := store.Begin(ctx, scopedKey, fingerprint)
+		if err != nil {
+			zap.L().Error("idempotency store error", zap.Error(err))
+			c.Next()
+			return
+		}
+
+		if !acquired {
+			switch {
+			case existing.Fingerprint != fingerprint:
+				response.ErrorResponse(c, response.IdempotencyKeyMismatchError)
+			case existing.Status == idempotency.StatusProcessing:
+				c.Header("Retry-After", "1")
+				response.ErrorResponse(c, response.IdempotencyInProgressError)
+			default:
+				c.Header(idempotentReplayedHeader, "true")
+				c.Data(existing.StatusCode, existing.ContentType, existing.Body)
+			}
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		storeCtx := context.WithoutCancel(ctx)
+		status := recorder.Status()
+		contentType := recorder.Header().Get("Content-Type")
+		noStore := strings.Contains(recorder.Header().Get("Cache-Control"), "no-store")
+		if status >= http.StatusInternalServerError || status == http.StatusTooManyRequests || recorder.overflow || noStore || strings.HasPrefix(contentType, "text/event-stream") {
+			if err := store.Release(storeCtx, scopedKey); err != nil {
+				zap.L().Error("idempotency release error", zap.Error(err))
+			}
+			return
+		}
+
+		if err := store.Complete(storeCtx, scopedKey, &idempotency.Record{
+			Fingerprint: fingerprint,
+			StatusCode:  status,
+			ContentType: contentType,
+			Body:        recorder.body.Bytes(),
+		}); err != nil {
+			zap.L().Error("idempotency complete error", zap.Error(err))
+		}
+	}
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// idempotencyScope keys the stored response under the user, so account
+// deletion can find it, and under a digest of the request target, so one key
+// reused on another route starts a new request.
+func idempotencyScope(c *gin.Context, key string) string {
+	target := sha256.Sum256([]byte(c.Request.Method + " " + c.Request.URL.RequestURI()))
+	return fmt.Sprintf("user:%d:%s:%s", c.GetInt(string(UserIDKey)), hex.EncodeToString(target[:8]), key)
+}
+
+func requestFingerprint(c *gin.Context, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(c.Request.Method))
+	h.Write([]byte(c.Request.URL.RequestURI()))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
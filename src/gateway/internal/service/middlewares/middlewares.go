@@ -3,5 +3,5 @@ package middlewares
 import "github.com/google/wire"
 
 var ProviderSet = wire.NewSet(
-	NewDefaultIPRateLimiter,
+	NewRateLimiter,
 )
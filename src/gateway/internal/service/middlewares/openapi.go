@@ -0,0 +1,72 @@
+package middlewares
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/apispec"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/response"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// OpenAPIValidator rejects requests that do not match the gateway OpenAPI
+// document with FormError. Routes missing from the document pass through.
+// In dev mode JSON responses are checked as well and mismatches are logged,
+// so drift between handlers and the spec shows up before clients notice it.
+func OpenAPIValidator(spec *apispec.Spec) gin.HandlerFunc {
+	validateResponses := viper.GetString("project.mode") == "dev"
+	options := &openapi3filter.Options{
+		// Auth and Permission already guard the secured operations.
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(c *gin.Context) {
+		route, pathParams, err := spec.FindRoute(c.Request)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		requestInput := &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		}
+		if err := openapi3filter.ValidateRequest(ctx, requestInput); err != nil {
+			zap.L().Warn("request does not match openapi spec", zap.String("path", c.FullPath()), zap.Error(err))
+			response.ErrorResponse(c, response.FormError)
+			c.Abort()
+			return
+		}
+
+		if !validateResponses || websocket.IsWebSocketUpgrade(c.Request) {
+			c.Next()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		if recorder.overflow || !strings.HasPrefix(recorder.Header().Get("Content-Type"), "application/json") {
+			return
+		}
+		responseInput := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: requestInput,
+			Status:                 recorder.Status(),
+			Header:                 recorder.Header(),
+			Body:                   io.NopCloser(bytes.NewReader(recorder.body.Bytes())),
+			Options:                options,
+		}
+		if err := openapi3filter.ValidateResponse(ctx, responseInput); err != nil {
+			zap.L().Warn("response does not match openapi spec", zap.String("path", c.FullPath()), zap.Int("status", recorder.Status()), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package middlewares
+
+import (
+	"slices"
+
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/biz"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/response"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+var AccessKey = ContextKey("access")
+
+// Permission only lets through users whose role grants permission. Roles are
+// looked up in the user service on every request, so a demotion or a
+// disabled account takes effect immediately. It must run after Auth.
+func Permission(userUseCase biz.UserUseCase, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetInt(string(UserIDKey))
+
+		access, errorCode, err := userUseCase.GetAccess(c.Request.Context(), userID)
+		if err != nil {
+			zap.L().Error("get user access error", zap.Int("userID", userID), zap.Error(err))
+			response.AuthErrorResponse(c, errorCode)
+			return
+		}
+		if access.Disabled {
+			response.AuthErrorResponse(c, response.AccountDisabledError)
+			return
+		}
+		if !slices.Contains(access.Permissions, permission) {
+			zap.L().Warn("permission denied",
+				zap.Int("userID", userID),
+				zap.String("role", access.Role),
+				zap.String("permission", permission),
+				zap.String("path", c.FullPath()))
+			response.AuthErrorResponse(c, response.PermissionDeniedError)
+			return
+		}
+
+		c.Set(string(AccessKey), access)
+		c.Next()
+	}
+}
@@ -0,0 +1,36 @@
+package middlewares
+
+import (
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/biz"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/response"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Quota rejects requests from users who have used up their token plan. Usage
+// store failures are logged and the request is let through.
+func Quota(usageUseCase biz.UsageUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if errorCode := CheckQuota(c, usageUseCase); errorCode != response.NoError {
+			response.ErrorResponse(c, errorCode)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// CheckQuota returns QuotaExceededError once the caller has used up its plan
+// and NoError otherwise, including when the usage store fails.
+func CheckQuota(c *gin.Context, usageUseCase biz.UsageUseCase) response.ErrorCode {
+	userID := c.GetInt(string(UserIDKey))
+	errorCode, err := usageUseCase.CheckQuota(c.Request.Context(), userID, IsGuest(c))
+	if err != nil {
+		if errorCode == response.QuotaExceededError {
+			return errorCode
+		}
+		zap.L().Error("quota check error", zap.Int("userID", userID), zap.Error(err))
+	}
+	return response.NoError
+}
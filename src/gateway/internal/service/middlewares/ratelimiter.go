@@ -1,56 +1,140 @@
 package middlewares
 
 import (
-	"sync"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/ratelimit"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/response"
 	"github.com/gin-gonic/gin"
-	"golang.org/x/time/rate"
+	"go.uber.org/zap"
 )
 
-type IPRateLimiter struct {
-	ips map[string]*rate.Limiter
-	mu  *sync.RWMutex
-	r   rate.Limit
-	b   int
-}
+const rateLimitEvaluatedKey = "ratelimit_evaluated"
 
-func NewIPRateLimiter(r rate.Limit, b int) *IPRateLimiter {
-	return &IPRateLimiter{
-		ips: make(map[string]*rate.Limiter),
-		mu:  &sync.RWMutex{},
-		r:   r,
-		b:   b,
-	}
+type RateLimiter struct {
+	limiter ratelimit.Limiter
+	rules   ratelimit.Rules
 }
 
-func (i *IPRateLimiter) GetLimiter(ip string) *rate.Limiter {
-	i.mu.Lock()
-	defer i.mu.Unlock()
-
-	limiter, exists := i.ips[ip]
-	if !exists {
-		limiter = rate.NewLimiter(i.r, i.b)
-		i.ips[ip] = limiter
+func NewRateLimiter(limiter ratelimit.Limiter, rules ratelimit.Rules) *RateLimiter {
+	return &RateLimiter{
+		limiter: limiter,
+		rules:   rules,
 	}
-
-	return limiter
 }
 
-func IPRateLimitMiddleware(limiter *IPRateLimiter) gin.HandlerFunc {
+func RateLimitMiddleware(rl *RateLimiter) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ip := c.ClientIP()
-		l := limiter.GetLimiter(ip)
+		evaluated, _ := c.Get(rateLimitEvaluatedKey)
+		done, _ := evaluated.(map[string]bool)
+		if done == nil {
+			done = make(map[string]bool)
+			c.Set(rateLimitEvaluatedKey, done)
+		}
 
-		if !l.Allow() {
+		tightest, denied := rl.evaluate(c, done)
+		if denied != nil {
+			setRateLimitHeaders(c, denied)
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(denied.RetryAfter)))
 			response.ErrorResponse(c, response.RateLimitError)
 			c.Abort()
 			return
 		}
+
+		if tightest != nil {
+			setRateLimitHeaders(c, tightest)
+		}
 		c.Next()
 	}
 }
 
-func NewDefaultIPRateLimiter() *IPRateLimiter {
-	return NewIPRateLimiter(10, 20)
+// Allow evaluates every rule matching the route of c again. Long-lived
+// connections call it for each message, since the middleware only sees the
+// request that opened them.
+func (rl *RateLimiter) Allow(c *gin.Context) (*ratelimit.Result, bool) {
+	_, denied := rl.evaluate(c, make(map[string]bool))
+	return denied, denied == nil
+}
+
+// evaluate consumes a token from each matching rule not yet in done. It
+// returns the result with the fewest remaining tokens, or the first denial.
+func (rl *RateLimiter) evaluate(c *gin.Context, done map[string]bool) (tightest, denied *ratelimit.Result) {
+	route := c.FullPath()
+
+	for _, rule := range rl.rules {
+		if done[rule.Name] || !matchRoute(rule, route) {
+			continue
+		}
+
+		key, ok := rateLimitKey(c, rule, route)
+		if !ok {
+			continue
+		}
+		done[rule.Name] = true
+
+		result, err := rl.limiter.Allow(c.Request.Context(), key, rule)
+		if err != nil {
+			zap.L().Error("rate limiter error", zap.String("rule", rule.Name), zap.Error(err))
+			continue
+		}
+
+		if !result.Allowed {
+			return nil, result
+		}
+
+		if tightest == nil || result.Remaining < tightest.Remaining {
+			tightest = result
+		}
+	}
+	return tightest, nil
+}
+
+func matchRoute(rule *ratelimit.Rule, route string) bool {
+	if len(rule.Routes) == 0 {
+		return true
+	}
+	for _, r := range rule.Routes {
+		if prefix, ok := strings.CutSuffix(r, "*"); ok {
+			if strings.HasPrefix(route, prefix) {
+				return true
+			}
+		} else if r == route {
+			return true
+		}
+	}
+	return false
+}
+
+func rateLimitKey(c *gin.Context, rule *ratelimit.Rule, route string) (string, bool) {
+	var key string
+	switch rule.Key {
+	case ratelimit.KeyUser:
+		if _, exists := c.Get(string(UserIDKey)); !exists {
+			return "", false
+		}
+		key = fmt.Sprintf("user:%d", c.GetInt(string(UserIDKey)))
+	case ratelimit.KeyRoute:
+		return "route:" + route, true
+	default:
+		key = "ip:" + c.ClientIP()
+	}
+
+	if len(rule.Routes) > 0 {
+		key += ":" + route
+	}
+	return key, true
+}
+
+func setRateLimitHeaders(c *gin.Context, result *ratelimit.Result) {
+	c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
 }
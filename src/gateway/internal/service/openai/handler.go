@@ -0,0 +1,303 @@
+package openai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/biz"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/models"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/response"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/middlewares"
+	mateapi "github.com/Fl0rencess720/Doria/src/rpc/mate"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+type OpenAIHandler struct {
+	mateUseCase biz.MateUseCase
+	personas    []models.Persona
+	createdAt   int64
+}
+
+func NewOpenAIHandler(mateUseCase biz.MateUseCase) *OpenAIHandler {
+	personas := []models.Persona{}
+	if err := viper.UnmarshalKey("openai.personas", &personas); err != nil {
+		zap.L().Error("failed to load openai personas", zap.Error(err))
+	}
+	if len(personas) == 0 {
+		personas = append(personas, models.Persona{ID: "doria"})
+	}
+
+	return &OpenAIHandler{
+		mateUseCase: mateUseCase,
+		personas:    personas,
+		createdAt:   time.Now().Unix(),
+	}
+}
+
+func (h *OpenAIHandler) ListModels(c *gin.Context) {
+	data := make([]models.ModelResp, len(h.personas))
+	for i, persona := range h.personas {
+		data[i] = models.ModelResp{
+			ID:          persona.ID,
+			Object:      "model",
+			Created:     h.createdAt,
+			OwnedBy:     "doria",
+			Description: persona.Description,
+		}
+	}
+
+	c.JSON(http.StatusOK, models.ModelListResp{
+		Object: "list",
+		Data:   data,
+	})
+}
+
+func (h *OpenAIHandler) ChatCompletions(c *gin.Context) {
+	userID := c.GetInt(string(middlewares.UserIDKey))
+
+	req := &models.ChatCompletionReq{}
+	if err := c.ShouldBindJSON(req); err != nil {
+		zap.L().Warn("request bind error", zap.Error(err))
+		errorResponse(c, http.StatusBadRequest, "invalid_request_error", err.Error())
+		return
+	}
+
+	if !h.hasPersona(req.Model) {
+		errorResponse(c, http.StatusNotFound, "invalid_request_error", fmt.Sprintf("The model `%s` does not exist", req.Model))
+		return
+	}
+
+	chatReq, err := newChatReq(req)
+	if err != nil {
+		errorResponse(c, http.StatusBadRequest, "invalid_request_error", err.Error())
+		return
+	}
+
+	if req.Stream {
+		h.chatCompletionsStream(c, req, chatReq, userID)
+		return
+	}
+
+	resp, errorCode, err := h.mateUseCase.Chat(c.Request.Context(), chatReq, userID)
+	if err != nil {
+		zap.L().Error("chat completion error", zap.Error(err))
+		errorCodeResponse(c, errorCode)
+		return
+	}
+	if errorCode == response.DegradedError {
+		errorResponse(c, http.StatusServiceUnavailable, "server_error", resp.Message)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ChatCompletionResp{
+		ID:      "chatcmpl-" + uuid.New().String(),
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   req.Model,
+		Choices: []models.ChatCompletionChoice{{
+			Index: 0,
+			Message: models.ChatCompletionRespMessage{
+				Role:    "assistant",
+				Content: resp.Message,
+			},
+			FinishReason: "stop",
+		}},
+		Usage: toUsage(resp.Usage),
+	})
+}
+
+func (h *OpenAIHandler) chatCompletionsStream(c *gin.Context, req *models.ChatCompletionReq, chatReq *models.ChatReq, userID int) {
+	ctx := c.Request.Context()
+
+	stream, err := h.mateUseCase.CreateChatStream(ctx, chatReq, userID)
+	if err != nil {
+		zap.L().Error("create chat stream error", zap.Error(err))
+		errorCodeResponse(c, response.FromError(err))
+		return
+	}
+
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+
+	flusher, ok := c.Writer.(http.Flusher)
+	if !ok {
+		zap.L().Error("ResponseWriter does not support Flusher")
+		return
+	}
+
+	id := "chatcmpl-" + uuid.New().String()
+	created := time.Now().Unix()
+	newChunk := func(delta models.ChatCompletionDelta, finishReason *string) *models.ChatCompletionChunk {
+		return &models.ChatCompletionChunk{
+			ID:      id,
+			Object:  "chat.completion.chunk",
+			Created: created,
+			Model:   req.Model,
+			Choices: []models.ChatCompletionChunkChoice{{
+				Index:        0,
+				Delta:        delta,
+				FinishReason: finishReason,
+			}},
+		}
+	}
+
+	if err := writeEvent(c.Writer, flusher, newChunk(models.ChatCompletionDelta{Role: "assistant"}, nil)); err != nil {
+		return
+	}
+
+	var metered *mateapi.Usage
+	for {
+		resp, err := stream.Recv()
+		if err != nil && err != io.EOF {
+			zap.L().Error("failed to receive from gRPC stream", zap.Error(err))
+			writeEvent(c.Writer, flusher, models.OpenAIErrorResp{
+				Error: models.OpenAIError{Message: err.Error(), Type: "server_error"},
+			})
+			return
+		}
+
+		if err == io.EOF {
+			break
+		}
+		if resp.Finished {
+			metered = resp.Usage
+			break
+		}
+
+		if err := writeEvent(c.Writer, flusher, newChunk(models.ChatCompletionDelta{Content: resp.Content}, nil)); err != nil {
+			zap.L().Error("Error writing to SSE stream (client disconnected?)", zap.Error(err))
+			return
+		}
+	}
+
+	stop := "stop"
+	final := newChunk(models.ChatCompletionDelta{}, &stop)
+	if req.StreamOptions != nil && req.StreamOptions.IncludeUsage {
+		usage := toUsage(metered)
+		final.Usage = &usage
+	}
+	if err := writeEvent(c.Writer, flusher, final); err != nil {
+		return
+	}
+
+	fmt.Fprint(c.Writer, "data: [DONE]\n\n")
+	flusher.Flush()
+}
+
+func (h *OpenAIHandler) hasPersona(model string) bool {
+	for _, persona := range h.personas {
+		if persona.ID == model {
+			return true
+		}
+	}
+	return false
+}
+
+func writeEvent(w io.Writer, flusher http.Flusher, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+		return err
+	}
+	flusher.Flush()
+	return nil
+}
+
+func errorResponse(c *gin.Context, status int, errType, message string) {
+	c.AbortWithStatusJSON(status, models.OpenAIErrorResp{
+		Error: models.OpenAIError{
+			Message: message,
+			Type:    errType,
+		},
+	})
+}
+
+// errorCodeResponse reports a gateway error code in the OpenAI error format,
+// with the HTTP status the rest of the API uses for it.
+func errorCodeResponse(c *gin.Context, code response.ErrorCode) {
+	status, ok := response.HttpCode[code]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+
+	errType := "server_error"
+	if status < http.StatusInternalServerError {
+		errType = "invalid_request_error"
+	}
+	errorResponse(c, status, errType, response.Message[code])
+}
+
+// newChatReq maps an OpenAI message list onto a mate chat: system and
+// developer messages become instructions, the final user message the prompt
+// and the turns before it the history. Anything else can't be honoured and
+// is rejected.
+func newChatReq(req *models.ChatCompletionReq) (*models.ChatReq, error) {
+	chatReq := &models.ChatReq{
+		Persona:        req.Model,
+		ConversationID: req.ConversationID,
+	}
+
+	var instructions []string
+	for i, message := range req.Messages {
+		content := messageText(message)
+		switch message.Role {
+		case "system", "developer":
+			instructions = append(instructions, content)
+		case "user", "assistant":
+			if i < len(req.Messages)-1 {
+				chatReq.History = append(chatReq.History, models.ChatMessage{Role: message.Role, Content: content})
+			} else if message.Role == "user" {
+				chatReq.Prompt = content
+			}
+		default:
+			return nil, fmt.Errorf("messages with role `%s` are not supported", message.Role)
+		}
+	}
+
+	if chatReq.Prompt == "" {
+		return nil, errors.New("the last message must be a non-empty user message")
+	}
+	chatReq.Instructions = strings.Join(instructions, "\n\n")
+	return chatReq, nil
+}
+
+func messageText(message models.ChatCompletionMessage) string {
+	switch content := message.Content.(type) {
+	case string:
+		return content
+	case []any:
+		var parts []string
+		for _, part := range content {
+			p, ok := part.(map[string]any)
+			if !ok || p["type"] != "text" {
+				continue
+			}
+			if text, ok := p["text"].(string); ok {
+				parts = append(parts, text)
+			}
+		}
+		return strings.Join(parts, "\n")
+	}
+	return ""
+}
+
+// toUsage reports the tokens mate metered for the request. A degraded reply
+// never reached a model and has none.
+func toUsage(usage *mateapi.Usage) models.ChatCompletionUsage {
+	return models.ChatCompletionUsage{
+		PromptTokens:     int(usage.GetPromptTokens()),
+		CompletionTokens: int(usage.GetCompletionTokens()),
+		TotalTokens:      int(usage.GetTotalTokens()),
+	}
+}
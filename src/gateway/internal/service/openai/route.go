@@ -0,0 +1,10 @@
+package openai
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func InitApi(group *gin.RouterGroup, openaiHandler *OpenAIHandler, quota gin.HandlerFunc) {
+	group.POST("/chat/completions", quota, openaiHandler.ChatCompletions)
+	group.GET("/models", openaiHandler.ListModels)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/Fl0rencess720/Doria/src/common/shutdown"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/biz"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/models"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/response"
@@ -21,17 +22,27 @@ var upgrader = websocket.Upgrader{
 
 type SignalingHandler struct {
 	signalingUseCase biz.SignalingUseCase
+	shutdown         *shutdown.Coordinator
 }
 
-func NewSignalingHandler(signalingUseCase biz.SignalingUseCase) *SignalingHandler {
+func NewSignalingHandler(signalingUseCase biz.SignalingUseCase, coordinator *shutdown.Coordinator) *SignalingHandler {
 	return &SignalingHandler{
 		signalingUseCase: signalingUseCase,
+		shutdown:         coordinator,
 	}
 }
 
 func (h *SignalingHandler) Register(c *gin.Context) {
 	ctx := c.Request.Context()
 
+	// New sessions would need a chat stream this instance no longer accepts.
+	// Offers are still served so in-flight TTS pipelines can connect.
+	if h.shutdown.IsDraining() {
+		c.Header("Retry-After", "1")
+		response.ErrorResponse(c, response.DegradedError)
+		return
+	}
+
 	sessionID := utils.GenerateUniqueID()
 	peerID := utils.GenerateAnswerPeerID(sessionID)
 
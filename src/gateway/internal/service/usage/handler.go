@@ -0,0 +1,34 @@
+package usage
+
+import (
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/biz"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/response"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/middlewares"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type UsageHandler struct {
+	usageUseCase biz.UsageUseCase
+}
+
+func NewUsageHandler(usageUseCase biz.UsageUseCase) *UsageHandler {
+	return &UsageHandler{
+		usageUseCase: usageUseCase,
+	}
+}
+
+func (u *UsageHandler) GetUsage(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID := c.GetInt(string(middlewares.UserIDKey))
+
+	resp, errorCode, err := u.usageUseCase.GetUsage(ctx, userID, middlewares.IsGuest(c))
+	if err != nil {
+		zap.L().Error("GetUsage error", zap.Error(err))
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, resp)
+}
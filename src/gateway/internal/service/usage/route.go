@@ -0,0 +1,9 @@
+package usage
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func InitApi(group *gin.RouterGroup, usageHandler *UsageHandler) {
+	group.GET("", usageHandler.GetUsage)
+}
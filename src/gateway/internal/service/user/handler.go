@@ -1,9 +1,15 @@
 package user
 
 import (
+	"errors"
+	"io"
+	"net/http"
+
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/biz"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/models"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/jwtc"
 	"github.com/Fl0rencess720/Doria/src/gateway/internal/pkgs/response"
+	"github.com/Fl0rencess720/Doria/src/gateway/internal/service/middlewares"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -28,6 +34,10 @@ func (h *UserHandler) Register(c *gin.Context) {
 		return
 	}
 
+	if req.Device == "" {
+		req.Device = c.Request.UserAgent()
+	}
+
 	resp, errorCode, err := h.userUseCase.Register(ctx, &req)
 	if err != nil {
 		response.ErrorResponse(c, errorCode)
@@ -47,7 +57,209 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
+	if req.Device == "" {
+		req.Device = c.Request.UserAgent()
+	}
+	req.ClientIP = c.ClientIP()
+
 	resp, errorCode, err := h.userUseCase.Login(ctx, &req)
+	if err != nil {
+		response.SetRetryAfter(c, err)
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, resp)
+}
+
+func (h *UserHandler) SendVerificationCode(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	req := models.SendCodeReq{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		zap.L().Error("request bind error", zap.Error(err))
+		response.ErrorResponse(c, response.FormError)
+		return
+	}
+
+	resp, errorCode, err := h.userUseCase.SendVerificationCode(ctx, &req, c.ClientIP())
+	if err != nil {
+		response.SetRetryAfter(c, err)
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, resp)
+}
+
+func (h *UserHandler) ChangePhone(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	req := models.ChangePhoneReq{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		zap.L().Error("request bind error", zap.Error(err))
+		response.ErrorResponse(c, response.FormError)
+		return
+	}
+
+	if req.Device == "" {
+		req.Device = c.Request.UserAgent()
+	}
+
+	userID := c.GetInt(string(middlewares.UserIDKey))
+
+	resp, errorCode, err := h.userUseCase.ChangePhone(ctx, userID, &req)
+	if err != nil {
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, resp)
+}
+
+func (h *UserHandler) GuestLogin(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	req := models.GuestLoginReq{}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		zap.L().Error("request bind error", zap.Error(err))
+		response.ErrorResponse(c, response.FormError)
+		return
+	}
+
+	if req.Device == "" {
+		req.Device = c.Request.UserAgent()
+	}
+
+	resp, errorCode, err := h.userUseCase.GuestLogin(ctx, &req)
+	if err != nil {
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, resp)
+}
+
+func (h *UserHandler) UpgradeGuest(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	req := models.UpgradeGuestReq{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		zap.L().Error("request bind error", zap.Error(err))
+		response.ErrorResponse(c, response.FormError)
+		return
+	}
+
+	if req.Device == "" {
+		req.Device = c.Request.UserAgent()
+	}
+
+	userID := c.GetInt(string(middlewares.UserIDKey))
+
+	resp, errorCode, err := h.userUseCase.UpgradeGuest(ctx, userID, &req)
+	if err != nil {
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, resp)
+}
+
+func (h *UserHandler) GetProfile(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID := c.GetInt(string(middlewares.UserIDKey))
+
+	resp, errorCode, err := h.userUseCase.GetProfile(ctx, userID)
+	if err != nil {
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, resp)
+}
+
+func (h *UserHandler) UpdateProfile(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	req := models.UpdateProfileReq{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		zap.L().Error("request bind error", zap.Error(err))
+		response.ErrorResponse(c, response.FormError)
+		return
+	}
+
+	userID := c.GetInt(string(middlewares.UserIDKey))
+
+	resp, errorCode, err := h.userUseCase.UpdateProfile(ctx, userID, &req)
+	if err != nil {
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, resp)
+}
+
+func (h *UserHandler) RequestPasswordReset(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	req := models.RequestPasswordResetReq{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		zap.L().Error("request bind error", zap.Error(err))
+		response.ErrorResponse(c, response.FormError)
+		return
+	}
+
+	resp, errorCode, err := h.userUseCase.RequestPasswordReset(ctx, &req, c.ClientIP())
+	if err != nil {
+		response.SetRetryAfter(c, err)
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, resp)
+}
+
+func (h *UserHandler) ResetPassword(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	req := models.ResetPasswordReq{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		zap.L().Error("request bind error", zap.Error(err))
+		response.ErrorResponse(c, response.FormError)
+		return
+	}
+
+	if req.Device == "" {
+		req.Device = c.Request.UserAgent()
+	}
+
+	resp, errorCode, err := h.userUseCase.ResetPassword(ctx, &req)
+	if err != nil {
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, resp)
+}
+
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	req := models.ChangePasswordReq{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		zap.L().Error("request bind error", zap.Error(err))
+		response.ErrorResponse(c, response.FormError)
+		return
+	}
+
+	if req.Device == "" {
+		req.Device = c.Request.UserAgent()
+	}
+
+	userID := c.GetInt(string(middlewares.UserIDKey))
+
+	resp, errorCode, err := h.userUseCase.ChangePassword(ctx, userID, &req)
 	if err != nil {
 		response.ErrorResponse(c, errorCode)
 		return
@@ -74,3 +286,77 @@ func (h *UserHandler) Refresh(c *gin.Context) {
 
 	response.SuccessResponse(c, resp)
 }
+
+func (h *UserHandler) Logout(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID := c.GetInt(string(middlewares.UserIDKey))
+	sessionID := c.GetString(string(middlewares.SessionIDKey))
+
+	errorCode, err := h.userUseCase.Logout(ctx, userID, sessionID)
+	if err != nil {
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, nil)
+}
+
+func (h *UserHandler) LogoutAll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userID := c.GetInt(string(middlewares.UserIDKey))
+
+	errorCode, err := h.userUseCase.LogoutAll(ctx, userID)
+	if err != nil {
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, nil)
+}
+
+func (h *UserHandler) DeleteAccount(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	req := models.DeleteAccountReq{}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		zap.L().Error("request bind error", zap.Error(err))
+		response.ErrorResponse(c, response.FormError)
+		return
+	}
+
+	userID := c.GetInt(string(middlewares.UserIDKey))
+
+	resp, errorCode, err := h.userUseCase.DeleteAccount(ctx, userID, &req)
+	if err != nil {
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, resp)
+}
+
+func (h *UserHandler) GetAccountDeletion(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	req := models.GetAccountDeletionReq{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		zap.L().Error("request bind error", zap.Error(err))
+		response.ErrorResponse(c, response.FormError)
+		return
+	}
+
+	resp, errorCode, err := h.userUseCase.GetAccountDeletion(ctx, c.Param("id"), &req)
+	if err != nil {
+		response.ErrorResponse(c, errorCode)
+		return
+	}
+
+	response.SuccessResponse(c, resp)
+}
+
+func (h *UserHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, jwtc.PublicJWKS())
+}
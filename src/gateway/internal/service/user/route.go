@@ -4,11 +4,28 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func InitApi(group *gin.RouterGroup, userHandler *UserHandler) {
-	group.POST("/refresh", userHandler.Refresh)
+func InitApi(group *gin.RouterGroup, userHandler *UserHandler, fullAccount, noStore gin.HandlerFunc) {
+	group.POST("/logout", userHandler.Logout)
+	group.POST("/logout/all", userHandler.LogoutAll)
+	group.PUT("/phone", fullAccount, noStore, userHandler.ChangePhone)
+	group.PUT("/password", fullAccount, noStore, userHandler.ChangePassword)
+	group.POST("/guest/upgrade", noStore, userHandler.UpgradeGuest)
+	group.GET("/profile", userHandler.GetProfile)
+	group.PATCH("/profile", userHandler.UpdateProfile)
+	group.DELETE("/account", userHandler.DeleteAccount)
 }
 
-func InitNoneAuthApi(group *gin.RouterGroup, userHandler *UserHandler) {
-	group.POST("/register", userHandler.Register)
-	group.POST("/login", userHandler.Login)
+func InitNoneAuthApi(group *gin.RouterGroup, userHandler *UserHandler, noStore gin.HandlerFunc) {
+	group.POST("/register", noStore, userHandler.Register)
+	group.POST("/login", noStore, userHandler.Login)
+	group.POST("/guest", noStore, userHandler.GuestLogin)
+	group.POST("/refresh", noStore, userHandler.Refresh)
+	group.POST("/code", userHandler.SendVerificationCode)
+	group.POST("/password/reset/request", userHandler.RequestPasswordReset)
+	group.POST("/password/reset", noStore, userHandler.ResetPassword)
+	group.POST("/account/deletions/:id", userHandler.GetAccountDeletion)
+}
+
+func InitWellKnownApi(group *gin.RouterGroup, userHandler *UserHandler) {
+	group.GET("/jwks.json", userHandler.JWKS)
 }
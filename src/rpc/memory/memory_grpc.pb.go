@@ -19,8 +19,11 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	MemoryService_GetMemory_FullMethodName   = "/memory.MemoryService/GetMemory"
-	MemoryService_GetMessages_FullMethodName = "/memory.MemoryService/GetMessages"
+	MemoryService_GetMemory_FullMethodName       = "/memory.MemoryService/GetMemory"
+	MemoryService_GetMessages_FullMethodName     = "/memory.MemoryService/GetMessages"
+	MemoryService_GetMemoryTiers_FullMethodName  = "/memory.MemoryService/GetMemoryTiers"
+	MemoryService_ReprocessMemory_FullMethodName = "/memory.MemoryService/ReprocessMemory"
+	MemoryService_PurgeUserMemory_FullMethodName = "/memory.MemoryService/PurgeUserMemory"
 )
 
 // MemoryServiceClient is the client API for MemoryService service.
@@ -29,6 +32,9 @@ const (
 type MemoryServiceClient interface {
 	GetMemory(ctx context.Context, in *GetMemoryRequest, opts ...grpc.CallOption) (*GetMemoryResponse, error)
 	GetMessages(ctx context.Context, in *GetMessagesRequest, opts ...grpc.CallOption) (*GetMessagesResponse, error)
+	GetMemoryTiers(ctx context.Context, in *GetMemoryTiersRequest, opts ...grpc.CallOption) (*GetMemoryTiersResponse, error)
+	ReprocessMemory(ctx context.Context, in *ReprocessMemoryRequest, opts ...grpc.CallOption) (*ReprocessMemoryResponse, error)
+	PurgeUserMemory(ctx context.Context, in *PurgeUserMemoryRequest, opts ...grpc.CallOption) (*PurgeUserMemoryResponse, error)
 }
 
 type memoryServiceClient struct {
@@ -59,12 +65,45 @@ func (c *memoryServiceClient) GetMessages(ctx context.Context, in *GetMessagesRe
 	return out, nil
 }
 
+func (c *memoryServiceClient) GetMemoryTiers(ctx context.Context, in *GetMemoryTiersRequest, opts ...grpc.CallOption) (*GetMemoryTiersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMemoryTiersResponse)
+	err := c.cc.Invoke(ctx, MemoryService_GetMemoryTiers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *memoryServiceClient) ReprocessMemory(ctx context.Context, in *ReprocessMemoryRequest, opts ...grpc.CallOption) (*ReprocessMemoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReprocessMemoryResponse)
+	err := c.cc.Invoke(ctx, MemoryService_ReprocessMemory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *memoryServiceClient) PurgeUserMemory(ctx context.Context, in *PurgeUserMemoryRequest, opts ...grpc.CallOption) (*PurgeUserMemoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PurgeUserMemoryResponse)
+	err := c.cc.Invoke(ctx, MemoryService_PurgeUserMemory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MemoryServiceServer is the server API for MemoryService service.
 // All implementations must embed UnimplementedMemoryServiceServer
 // for forward compatibility.
 type MemoryServiceServer interface {
 	GetMemory(context.Context, *GetMemoryRequest) (*GetMemoryResponse, error)
 	GetMessages(context.Context, *GetMessagesRequest) (*GetMessagesResponse, error)
+	GetMemoryTiers(context.Context, *GetMemoryTiersRequest) (*GetMemoryTiersResponse, error)
+	ReprocessMemory(context.Context, *ReprocessMemoryRequest) (*ReprocessMemoryResponse, error)
+	PurgeUserMemory(context.Context, *PurgeUserMemoryRequest) (*PurgeUserMemoryResponse, error)
 	mustEmbedUnimplementedMemoryServiceServer()
 }
 
@@ -81,6 +120,15 @@ func (UnimplementedMemoryServiceServer) GetMemory(context.Context, *GetMemoryReq
 func (UnimplementedMemoryServiceServer) GetMessages(context.Context, *GetMessagesRequest) (*GetMessagesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMessages not implemented")
 }
+func (UnimplementedMemoryServiceServer) GetMemoryTiers(context.Context, *GetMemoryTiersRequest) (*GetMemoryTiersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMemoryTiers not implemented")
+}
+func (UnimplementedMemoryServiceServer) ReprocessMemory(context.Context, *ReprocessMemoryRequest) (*ReprocessMemoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReprocessMemory not implemented")
+}
+func (UnimplementedMemoryServiceServer) PurgeUserMemory(context.Context, *PurgeUserMemoryRequest) (*PurgeUserMemoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeUserMemory not implemented")
+}
 func (UnimplementedMemoryServiceServer) mustEmbedUnimplementedMemoryServiceServer() {}
 func (UnimplementedMemoryServiceServer) testEmbeddedByValue()                       {}
 
@@ -138,6 +186,60 @@ func _MemoryService_GetMessages_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MemoryService_GetMemoryTiers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMemoryTiersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MemoryServiceServer).GetMemoryTiers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MemoryService_GetMemoryTiers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MemoryServiceServer).GetMemoryTiers(ctx, req.(*GetMemoryTiersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MemoryService_ReprocessMemory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReprocessMemoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MemoryServiceServer).ReprocessMemory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MemoryService_ReprocessMemory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MemoryServiceServer).ReprocessMemory(ctx, req.(*ReprocessMemoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MemoryService_PurgeUserMemory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeUserMemoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MemoryServiceServer).PurgeUserMemory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MemoryService_PurgeUserMemory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MemoryServiceServer).PurgeUserMemory(ctx, req.(*PurgeUserMemoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MemoryService_ServiceDesc is the grpc.ServiceDesc for MemoryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -153,6 +255,18 @@ var MemoryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetMessages",
 			Handler:    _MemoryService_GetMessages_Handler,
 		},
+		{
+			MethodName: "GetMemoryTiers",
+			Handler:    _MemoryService_GetMemoryTiers_Handler,
+		},
+		{
+			MethodName: "ReprocessMemory",
+			Handler:    _MemoryService_ReprocessMemory_Handler,
+		},
+		{
+			MethodName: "PurgeUserMemory",
+			Handler:    _MemoryService_PurgeUserMemory_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "memory.proto",
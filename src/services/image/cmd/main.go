@@ -6,6 +6,7 @@ import (
 
 	"github.com/Fl0rencess720/Doria/src/common/conf"
 	"github.com/Fl0rencess720/Doria/src/common/logging"
+	"github.com/Fl0rencess720/Doria/src/common/metrics"
 	"github.com/Fl0rencess720/Doria/src/common/profiling"
 	"github.com/Fl0rencess720/Doria/src/common/tracing"
 	"github.com/Fl0rencess720/Doria/src/services/image/configs"
@@ -23,6 +24,7 @@ func init() {
 	logging.Init()
 
 	profiling.InitPyroscope(configs.GetServiceName())
+	metrics.Serve(configs.GetServiceName())
 
 }
 
@@ -7,6 +7,7 @@
 package main
 
 import (
+	"github.com/Fl0rencess720/Doria/src/common/health"
 	"github.com/Fl0rencess720/Doria/src/services/image/configs"
 	"github.com/Fl0rencess720/Doria/src/services/image/internal/biz"
 	"github.com/Fl0rencess720/Doria/src/services/image/internal/data"
@@ -19,7 +20,8 @@ func wireApp() *App {
 	string2 := configs.GetServiceName()
 	imageRepo := data.NewImageRepo()
 	imageUseCase := biz.NewImageUseCase(imageRepo)
-	imageService := service.NewImageService(string2, imageUseCase)
+	checker := health.NewChecker()
+	imageService := service.NewImageService(string2, imageUseCase, checker)
 	app := NewApp(imageService)
 	return app
 }
@@ -3,6 +3,7 @@ package biz
 import (
 	"context"
 
+	"github.com/Fl0rencess720/Doria/src/common/rpcerr"
 	"github.com/Fl0rencess720/Doria/src/services/image/internal/pkgs/agent"
 )
 
@@ -26,7 +27,7 @@ func (*ImageUseCase) GenerateTextOfImage(ctx context.Context, imageData []byte,
 	}
 	response, err := textGenerator.Generator(ctx, imageData)
 	if err != nil {
-		return nil, err
+		return nil, rpcerr.Wrap(rpcerr.ReasonUpstreamLLM, err)
 	}
 	return response, nil
 }
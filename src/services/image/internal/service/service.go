@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
@@ -8,7 +9,10 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/Fl0rencess720/Doria/src/common/health"
+	"github.com/Fl0rencess720/Doria/src/common/metrics"
 	"github.com/Fl0rencess720/Doria/src/common/registry"
+	"github.com/Fl0rencess720/Doria/src/common/rpcerr"
 	imageapi "github.com/Fl0rencess720/Doria/src/rpc/image"
 	"github.com/Fl0rencess720/Doria/src/services/image/internal/biz"
 	"github.com/google/wire"
@@ -28,11 +32,12 @@ type ImageService struct {
 	registry    *registry.RegistrationManager
 	server      *grpc.Server
 	listener    net.Listener
+	health      *health.Checker
 
 	imageUseCase *biz.ImageUseCase
 }
 
-func NewImageService(serviceName string, imageUseCase *biz.ImageUseCase) *ImageService {
+func NewImageService(serviceName string, imageUseCase *biz.ImageUseCase, checker *health.Checker) *ImageService {
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", viper.GetInt("server.grpc.port")))
 	if err != nil {
 		panic(err)
@@ -51,15 +56,18 @@ func NewImageService(serviceName string, imageUseCase *biz.ImageUseCase) *ImageS
 	server := grpc.NewServer(
 		grpc.KeepaliveEnforcementPolicy(kaep),
 		grpc.KeepaliveParams(kasp),
+		grpc.ChainUnaryInterceptor(metrics.UnaryServerInterceptor(), rpcerr.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(metrics.StreamServerInterceptor(), rpcerr.StreamServerInterceptor()),
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 	)
 
 	registrationManager := registry.NewRegistrationManager()
 
-	s := &ImageService{serviceName: serviceName, registry: registrationManager, server: server, listener: lis,
+	s := &ImageService{serviceName: serviceName, registry: registrationManager, server: server, listener: lis, health: checker,
 		imageUseCase: imageUseCase}
 
 	imageapi.RegisterImageServiceServer(server, s)
+	checker.Register(server)
 
 	return s
 }
@@ -71,7 +79,8 @@ func (s *ImageService) Start() error {
 	}
 	s.serviceID = serviceID
 
-	go s.registry.SetTTLHealthCheck()
+	s.health.Start(context.Background())
+	go s.registry.SetTTLHealthCheck(s.health.Err)
 
 	go func() {
 		if err := s.server.Serve(s.listener); err != nil {
@@ -89,6 +98,7 @@ func (s *ImageService) Stop() error {
 				zap.Error(err))
 		}
 	}
+	s.health.Shutdown()
 	zap.L().Info("Shutting down gRPC server...")
 	s.server.GracefulStop()
 	return nil
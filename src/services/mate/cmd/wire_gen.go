@@ -7,6 +7,8 @@
 package main
 
 import (
+	"github.com/Fl0rencess720/Doria/src/common/metering"
+	"github.com/Fl0rencess720/Doria/src/common/shutdown"
 	"github.com/Fl0rencess720/Doria/src/services/mate/configs"
 	"github.com/Fl0rencess720/Doria/src/services/mate/internal/biz"
 	"github.com/Fl0rencess720/Doria/src/services/mate/internal/data"
@@ -22,8 +24,12 @@ func wireApp() *App {
 	client := data.NewRedis()
 	mateRepo := data.NewMateRepo(db, kafkaClient, client)
 	memoryServiceClient := data.NewMemoryClient()
-	mateUseCase := biz.NewMateUseCase(mateRepo, memoryServiceClient)
-	mateService := service.NewMateService(string2, mateUseCase)
+	userServiceClient := data.NewUserClient()
+	recorder := metering.NewRedisRecorder(client)
+	mateUseCase := biz.NewMateUseCase(mateRepo, memoryServiceClient, userServiceClient, recorder)
+	checker := data.NewHealthChecker(db, client)
+	coordinator := shutdown.NewCoordinator()
+	mateService := service.NewMateService(string2, mateUseCase, checker, coordinator)
 	app := NewApp(mateService)
 	return app
 }
@@ -0,0 +1,86 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Fl0rencess720/Doria/src/common/rpcerr"
+	"github.com/Fl0rencess720/Doria/src/services/mate/internal/models"
+)
+
+const defaultConversationTitle = "新对话"
+
+var (
+	ErrConversationNotFound = rpcerr.New(rpcerr.ReasonNotFound, "conversation not found")
+	ErrConversationArchived = rpcerr.New(rpcerr.ReasonValidation, "conversation is archived")
+)
+
+func (u *MateUseCase) CreateConversation(ctx context.Context, userID uint, title string) (*models.Conversation, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title = defaultConversationTitle
+	}
+
+	conversation := &models.Conversation{
+		UserID: userID,
+		Title:  title,
+	}
+	if err := u.repo.CreateConversation(ctx, conversation); err != nil {
+		return nil, err
+	}
+	return conversation, nil
+}
+
+func (u *MateUseCase) ListConversations(ctx context.Context, userID uint, includeArchived bool) ([]*models.Conversation, error) {
+	return u.repo.ListConversations(ctx, userID, includeArchived)
+}
+
+func (u *MateUseCase) RenameConversation(ctx context.Context, userID, conversationID uint, title string) (*models.Conversation, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title = defaultConversationTitle
+	}
+	return u.repo.UpdateConversation(ctx, userID, conversationID, map[string]any{"title": title})
+}
+
+func (u *MateUseCase) ArchiveConversation(ctx context.Context, userID, conversationID uint, archived bool) (*models.Conversation, error) {
+	return u.repo.UpdateConversation(ctx, userID, conversationID, map[string]any{"archived": archived})
+}
+
+func (u *MateUseCase) DeleteConversation(ctx context.Context, userID, conversationID uint) error {
+	return u.repo.DeleteConversation(ctx, userID, conversationID)
+}
+
+func (u *MateUseCase) resolveConversation(ctx context.Context, req *ChatReq) (uint, error) {
+	if req.ConversationID != 0 {
+		conversation, err := u.repo.GetConversation(ctx, req.UserID, req.ConversationID)
+		if err != nil {
+			return 0, err
+		}
+		if conversation.Archived {
+			return 0, ErrConversationArchived
+		}
+		return conversation.ID, nil
+	}
+
+	conversation, err := u.repo.GetLatestConversation(ctx, req.UserID)
+	if err == nil {
+		return conversation.ID, nil
+	}
+	if !errors.Is(err, ErrConversationNotFound) {
+		return 0, err
+	}
+
+	conversation, err = u.CreateConversation(ctx, req.UserID, "")
+	if err != nil {
+		return 0, err
+	}
+	return conversation.ID, nil
+}
+
+// PurgeUserData erases the conversations and usage counters of a deleted
+// account. Pages are left to the memory service, which owns their vectors.
+func (u *MateUseCase) PurgeUserData(ctx context.Context, userID uint) (*models.PurgeResult, error) {
+	return u.repo.PurgeUserData(ctx, userID)
+}
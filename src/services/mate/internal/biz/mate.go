@@ -3,10 +3,16 @@ package biz
 import (
 	"context"
 	"io"
+	"time"
 
+	"github.com/Fl0rencess720/Doria/src/common/metering"
+	"github.com/Fl0rencess720/Doria/src/common/metrics"
+	"github.com/Fl0rencess720/Doria/src/common/rpcerr"
 	memoryapi "github.com/Fl0rencess720/Doria/src/rpc/memory"
+	userapi "github.com/Fl0rencess720/Doria/src/rpc/user"
 	"github.com/Fl0rencess720/Doria/src/services/mate/internal/models"
 	"github.com/Fl0rencess720/Doria/src/services/mate/internal/pkgs/agent"
+	"github.com/cloudwego/eino/compose"
 	"github.com/cloudwego/eino/schema"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -16,11 +22,30 @@ type MateRepo interface {
 	SavePage(ctx context.Context, page *models.Page) error
 	SendMemorySignal(ctx context.Context, userID uint) error
 	GetUserPages(ctx context.Context, req *models.GetUserPagesRequest) (*models.GetUserPagesResponse, error)
+
+	CreateConversation(ctx context.Context, conversation *models.Conversation) error
+	GetConversation(ctx context.Context, userID, conversationID uint) (*models.Conversation, error)
+	GetLatestConversation(ctx context.Context, userID uint) (*models.Conversation, error)
+	ListConversations(ctx context.Context, userID uint, includeArchived bool) ([]*models.Conversation, error)
+	UpdateConversation(ctx context.Context, userID, conversationID uint, updates map[string]any) (*models.Conversation, error)
+	TouchConversation(ctx context.Context, conversationID uint) error
+	DeleteConversation(ctx context.Context, userID, conversationID uint) error
+
+	PurgeUserData(ctx context.Context, userID uint) (*models.PurgeResult, error)
 }
 
+// ErrUnknownPersona rejects a persona this agent can't speak as.
+var ErrUnknownPersona = rpcerr.New(rpcerr.ReasonValidation, "unknown persona")
+
+// profileTimeout bounds the profile lookup so a slow user service only costs
+// the reply its personal touch.
+const profileTimeout = 2 * time.Second
+
 type MateUseCase struct {
-	repo         MateRepo
-	memoryClient memoryapi.MemoryServiceClient
+	repo          MateRepo
+	memoryClient  memoryapi.MemoryServiceClient
+	userClient    userapi.UserServiceClient
+	usageRecorder metering.Recorder
 }
 
 type MessageResp struct {
@@ -30,26 +55,46 @@ type MessageResp struct {
 }
 
 type ChatReq struct {
-	UserID uint
-	Prompt string
+	UserID         uint
+	ConversationID uint
+	Prompt         string
+	Persona        string
+	Instructions   string
+	// History replaces the stored short and mid term memory when set.
+	History []*schema.Message
 }
 
-func NewMateUseCase(repo MateRepo, memoryClient memoryapi.MemoryServiceClient) *MateUseCase {
+func NewMateUseCase(repo MateRepo, memoryClient memoryapi.MemoryServiceClient, userClient userapi.UserServiceClient, usageRecorder metering.Recorder) *MateUseCase {
 	return &MateUseCase{
-		repo:         repo,
-		memoryClient: memoryClient,
+		repo:          repo,
+		memoryClient:  memoryClient,
+		userClient:    userClient,
+		usageRecorder: usageRecorder,
 	}
 }
 
-func (u *MateUseCase) Chat(ctx context.Context, req *ChatReq) (string, error) {
+func (u *MateUseCase) Chat(ctx context.Context, req *ChatReq) (string, metering.Usage, error) {
 	var (
 		mate *agent.Agent
 		err  error
 	)
 
-	memory, err := u.memoryClient.GetMemory(ctx, &memoryapi.GetMemoryRequest{UserId: int32(req.UserID), Prompt: req.Prompt})
+	if req.Persona != "" && req.Persona != agent.Persona {
+		return "", metering.Usage{}, ErrUnknownPersona
+	}
+
+	conversationID, err := u.resolveConversation(ctx, req)
 	if err != nil {
-		return "", err
+		return "", metering.Usage{}, err
+	}
+
+	memory, err := u.memoryClient.GetMemory(ctx, &memoryapi.GetMemoryRequest{
+		UserId:         int32(req.UserID),
+		Prompt:         req.Prompt,
+		ConversationId: uint32(conversationID),
+	})
+	if err != nil {
+		return "", metering.Usage{}, err
 	}
 
 	pages := make([]*models.Page, 0, len(memory.ShortTermMemory)+len(memory.MidTermMemory))
@@ -75,44 +120,67 @@ func (u *MateUseCase) Chat(ctx context.Context, req *ChatReq) (string, error) {
 
 	mate, err = agent.NewAgent(ctx)
 	if err != nil {
-		return "", err
+		return "", metering.Usage{}, err
 	}
 
+	tally := metering.NewTally(u.usageRecorder)
 	result, err := mate.Chat(ctx, &agent.AgentMemory{
-		QAparis:    pages,
-		Knowledges: knowledges,
-	}, req.Prompt)
+		QAparis:      pages,
+		Knowledges:   knowledges,
+		Profile:      u.loadProfile(ctx, req.UserID),
+		History:      req.History,
+		Instructions: req.Instructions,
+	}, req.Prompt, compose.WithCallbacks(metering.NewHandler(tally, req.UserID)))
 	if err != nil {
-		return "", err
+		return "", metering.Usage{}, rpcerr.Wrap(rpcerr.ReasonUpstreamLLM, err)
 	}
 
 	if err := u.repo.SavePage(ctx, &models.Page{
-		UserID:      req.UserID,
-		UserInput:   req.Prompt,
-		AgentOutput: result.Content,
-		Status:      "in_stm",
+		UserID:         req.UserID,
+		ConversationID: conversationID,
+		UserInput:      req.Prompt,
+		AgentOutput:    result.Content,
+		Status:         "in_stm",
 	}); err != nil {
-		return "", err
+		return "", metering.Usage{}, err
+	}
+
+	if err := u.repo.TouchConversation(ctx, conversationID); err != nil {
+		zap.L().Error("Failed to touch conversation", zap.Uint("conversationID", conversationID), zap.Error(err))
 	}
 
 	if err := u.repo.SendMemorySignal(ctx, req.UserID); err != nil {
-		return "", err
+		return "", metering.Usage{}, err
 	}
 
-	return result.Content, nil
+	return result.Content, tally.Usage(ctx), nil
 }
 
-func (u *MateUseCase) ChatStream(ctx context.Context, req *ChatReq) (*schema.StreamReader[string], string, error) {
+func (u *MateUseCase) ChatStream(ctx context.Context, req *ChatReq) (*schema.StreamReader[string], string, *metering.Tally, error) {
 	var (
 		mate *agent.Agent
 		err  error
 	)
 
+	start := time.Now()
 	messageID := uuid.New().String()
 
-	memory, err := u.memoryClient.GetMemory(ctx, &memoryapi.GetMemoryRequest{UserId: int32(req.UserID), Prompt: req.Prompt})
+	if req.Persona != "" && req.Persona != agent.Persona {
+		return nil, messageID, nil, ErrUnknownPersona
+	}
+
+	conversationID, err := u.resolveConversation(ctx, req)
+	if err != nil {
+		return nil, messageID, nil, err
+	}
+
+	memory, err := u.memoryClient.GetMemory(ctx, &memoryapi.GetMemoryRequest{
+		UserId:         int32(req.UserID),
+		Prompt:         req.Prompt,
+		ConversationId: uint32(conversationID),
+	})
 	if err != nil {
-		return nil, messageID, err
+		return nil, messageID, nil, err
 	}
 
 	pages := make([]*models.Page, 0, len(memory.ShortTermMemory)+len(memory.MidTermMemory))
@@ -138,15 +206,19 @@ func (u *MateUseCase) ChatStream(ctx context.Context, req *ChatReq) (*schema.Str
 
 	mate, err = agent.NewAgent(ctx)
 	if err != nil {
-		return nil, messageID, err
+		return nil, messageID, nil, err
 	}
 
+	tally := metering.NewTally(u.usageRecorder)
 	resultStream, err := mate.ChatStream(ctx, &agent.AgentMemory{
-		QAparis:    pages,
-		Knowledges: knowledges,
-	}, req.Prompt)
+		QAparis:      pages,
+		Knowledges:   knowledges,
+		Profile:      u.loadProfile(ctx, req.UserID),
+		History:      req.History,
+		Instructions: req.Instructions,
+	}, req.Prompt, compose.WithCallbacks(metering.NewHandler(tally, req.UserID)))
 	if err != nil {
-		return nil, messageID, err
+		return nil, messageID, nil, rpcerr.Wrap(rpcerr.ReasonUpstreamLLM, err)
 	}
 
 	wrappedReader, wrappedWriter := schema.Pipe[string](1)
@@ -154,38 +226,109 @@ func (u *MateUseCase) ChatStream(ctx context.Context, req *ChatReq) (*schema.Str
 		defer wrappedWriter.Close()
 		defer resultStream.Close()
 
-		var fullContent string
+		var (
+			fullContent string
+			firstChunk  = true
+		)
 
 		for {
 			chunk, err := resultStream.Recv()
+			if ctx.Err() != nil {
+				u.savePage(context.WithoutCancel(ctx), req, conversationID, fullContent, true)
+				return
+			}
 			if err == io.EOF {
-				if err := u.repo.SavePage(ctx, &models.Page{
-					UserID:      req.UserID,
-					UserInput:   req.Prompt,
-					AgentOutput: fullContent,
-					Status:      "in_stm",
-				}); err != nil {
-					zap.L().Error("Failed to save conversation", zap.Error(err))
-				}
-
-				if err := u.repo.SendMemorySignal(ctx, req.UserID); err != nil {
-					zap.L().Error("Failed to send memory signal", zap.Error(err))
-				}
+				u.savePage(ctx, req, conversationID, fullContent, false)
 				return
 			}
 			if err != nil {
-				wrappedWriter.Send("", err)
+				wrappedWriter.Send("", rpcerr.Wrap(rpcerr.ReasonUpstreamLLM, err))
 				return
 			}
 
+			if firstChunk && chunk != "" {
+				metrics.ChatFirstChunkSeconds.Observe(time.Since(start).Seconds())
+				firstChunk = false
+			}
+
 			fullContent += chunk
 			wrappedWriter.Send(chunk, nil)
 		}
 	}()
 
-	return wrappedReader, messageID, nil
+	return wrappedReader, messageID, tally, nil
+}
+
+// loadProfile fetches the user's profile for the prompt. Failures are logged
+// and the chat goes on without it.
+func (u *MateUseCase) loadProfile(ctx context.Context, userID uint) *agent.Profile {
+	ctx, cancel := context.WithTimeout(ctx, profileTimeout)
+	defer cancel()
+
+	resp, err := u.userClient.GetProfile(ctx, &userapi.GetProfileRequest{UserId: int32(userID)})
+	if err != nil {
+		zap.L().Warn("Failed to load user profile", zap.Uint("userID", userID), zap.Error(err))
+		return nil
+	}
+
+	profile := resp.GetProfile()
+	return &agent.Profile{
+		Nickname:  profile.GetNickname(),
+		AddressAs: profile.GetAddressAs(),
+		Timezone:  profile.GetTimezone(),
+		Locale:    profile.GetLocale(),
+	}
+}
+
+// savePage stores a streamed reply, marked truncated when the client stopped
+// it early, and lets the memory service know a new page is waiting.
+func (u *MateUseCase) savePage(ctx context.Context, req *ChatReq, conversationID uint, content string, truncated bool) {
+	if err := u.repo.SavePage(ctx, &models.Page{
+		UserID:         req.UserID,
+		ConversationID: conversationID,
+		UserInput:      req.Prompt,
+		AgentOutput:    content,
+		Truncated:      truncated,
+		Status:         "in_stm",
+	}); err != nil {
+		zap.L().Error("Failed to save conversation", zap.Bool("truncated", truncated), zap.Error(err))
+		return
+	}
+
+	if err := u.repo.TouchConversation(ctx, conversationID); err != nil {
+		zap.L().Error("Failed to touch conversation", zap.Uint("conversationID", conversationID), zap.Error(err))
+	}
+
+	if err := u.repo.SendMemorySignal(ctx, req.UserID); err != nil {
+		zap.L().Error("Failed to send memory signal", zap.Error(err))
+	}
 }
 
 func (u *MateUseCase) GetUserPages(ctx context.Context, req *models.GetUserPagesRequest) (*models.GetUserPagesResponse, error) {
 	return u.repo.GetUserPages(ctx, req)
 }
+
+// GetConversationMessages reads the transcript from the memory service, which
+// owns pages and how they turn into messages.
+func (u *MateUseCase) GetConversationMessages(ctx context.Context, req *models.GetMessagesRequest) ([]*MessageResp, error) {
+	resp, err := u.memoryClient.GetMessages(ctx, &memoryapi.GetMessagesRequest{
+		UserId:         int32(req.UserID),
+		ConversationId: uint32(req.ConversationID),
+		StartTime:      req.StartTime,
+		EndTime:        req.EndTime,
+		Limit:          int32(req.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*MessageResp, len(resp.Messages))
+	for i, message := range resp.Messages {
+		messages[i] = &MessageResp{
+			Role:       message.Role,
+			Content:    message.Content,
+			CreateTime: message.CreateTime,
+		}
+	}
+	return messages, nil
+}
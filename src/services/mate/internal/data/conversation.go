@@ -0,0 +1,113 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Fl0rencess720/Doria/src/consts"
+	"github.com/Fl0rencess720/Doria/src/services/mate/internal/biz"
+	"github.com/Fl0rencess720/Doria/src/services/mate/internal/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func (r *mateRepo) CreateConversation(ctx context.Context, conversation *models.Conversation) error {
+	return r.pg.WithContext(ctx).Create(conversation).Error
+}
+
+func (r *mateRepo) GetConversation(ctx context.Context, userID, conversationID uint) (*models.Conversation, error) {
+	conversation := &models.Conversation{}
+	if err := r.pg.WithContext(ctx).
+		Where("id = ? AND user_id = ?", conversationID, userID).
+		First(conversation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, biz.ErrConversationNotFound
+		}
+		return nil, err
+	}
+	return conversation, nil
+}
+
+func (r *mateRepo) GetLatestConversation(ctx context.Context, userID uint) (*models.Conversation, error) {
+	conversation := &models.Conversation{}
+	if err := r.pg.WithContext(ctx).
+		Where("user_id = ? AND archived = ?", userID, false).
+		Order("updated_at DESC, id DESC").
+		First(conversation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, biz.ErrConversationNotFound
+		}
+		return nil, err
+	}
+	return conversation, nil
+}
+
+func (r *mateRepo) ListConversations(ctx context.Context, userID uint, includeArchived bool) ([]*models.Conversation, error) {
+	conversations := []*models.Conversation{}
+	query := r.pg.WithContext(ctx).Where("user_id = ?", userID)
+	if !includeArchived {
+		query = query.Where("archived = ?", false)
+	}
+	if err := query.Order("updated_at DESC, id DESC").Find(&conversations).Error; err != nil {
+		return nil, err
+	}
+	return conversations, nil
+}
+
+func (r *mateRepo) UpdateConversation(ctx context.Context, userID, conversationID uint, updates map[string]any) (*models.Conversation, error) {
+	result := r.pg.WithContext(ctx).Model(&models.Conversation{}).
+		Where("id = ? AND user_id = ?", conversationID, userID).
+		Updates(updates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, biz.ErrConversationNotFound
+	}
+	return r.GetConversation(ctx, userID, conversationID)
+}
+
+func (r *mateRepo) TouchConversation(ctx context.Context, conversationID uint) error {
+	return r.pg.WithContext(ctx).Model(&models.Conversation{}).
+		Where("id = ?", conversationID).
+		Update("updated_at", gorm.Expr("NOW()")).Error
+}
+
+func (r *mateRepo) DeleteConversation(ctx context.Context, userID, conversationID uint) error {
+	var stmPages int64
+
+	err := r.pg.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", conversationID, userID).Delete(&models.Conversation{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return biz.ErrConversationNotFound
+		}
+
+		pageQuery := tx.Model(&models.Page{}).Where("conversation_id = ? AND user_id = ?", conversationID, userID)
+		if err := pageQuery.Session(&gorm.Session{}).Where("status = ?", "in_stm").Count(&stmPages).Error; err != nil {
+			return err
+		}
+
+		return pageQuery.Session(&gorm.Session{}).Update("status", "invalid").Error
+	})
+	if err != nil {
+		return err
+	}
+
+	pipe := r.redisClient.Pipeline()
+	pipe.Del(ctx, getConversationSTMCacheKey(userID, conversationID))
+	if stmPages > 0 {
+		pipe.IncrBy(ctx, fmt.Sprintf("%s:%d", consts.RedisSTMLengthKey, userID), -stmPages)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		zap.L().Error("Failed to clean up STM cache for deleted conversation",
+			zap.Uint("userID", userID),
+			zap.Uint("conversationID", conversationID),
+			zap.Error(err))
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package data
 
 import (
+	"github.com/Fl0rencess720/Doria/src/common/metering"
 	"github.com/Fl0rencess720/Doria/src/consts"
 	"github.com/google/wire"
 	"github.com/redis/go-redis/v9"
@@ -8,7 +9,7 @@ import (
 	"github.com/spf13/viper"
 )
 
-var ProviderSet = wire.NewSet(NewMateRepo, NewPostgres, NewMemoryClient, NewKafkaClient, NewRedis)
+var ProviderSet = wire.NewSet(NewMateRepo, NewPostgres, NewMemoryClient, NewUserClient, NewKafkaClient, NewRedis, NewHealthChecker, metering.NewRedisRecorder)
 
 type kafkaClient struct {
 	Writer *kafka.Writer
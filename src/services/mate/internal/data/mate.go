@@ -34,17 +34,21 @@ func NewMateRepo(pg *gorm.DB, kakafkaClient *kafkaClient, redisClient *redis.Cli
 func NewPostgres() *gorm.DB {
 	dsn := fmt.Sprintf(viper.GetString("database.postgres.dsn"), viper.GetString("POSTGRES_HOST"), viper.GetString("POSTGRES_PASSWORD"), viper.GetString("POSTGRES_PORT"))
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		PrepareStmt: true,
+		PrepareStmt:                              true,
+		DisableForeignKeyConstraintWhenMigrating: true,
 	})
 	if err != nil {
 		zap.L().Panic("failed to connect to postgres", zap.Error(err))
 	}
+	if err := migrate(db); err != nil {
+		zap.L().Panic("failed to migrate postgres", zap.Error(err))
+	}
 	return db
 }
 
 
-func getUserSTMCacheKey(userID uint) string {
-	return fmt.Sprintf("%s:%d", consts.STMPageCachePrefix, userID)
+func getConversationSTMCacheKey(userID, conversationID uint) string {
+	return fmt.Sprintf("%s:%d:%d", consts.STMPageCachePrefix, userID, conversationID)
 }
 
 func (r *mateRepo) SavePage(ctx context.Context, page *models.Page) error {
@@ -58,7 +62,7 @@ func (r *mateRepo) SavePage(ctx context.Context, page *models.Page) error {
 	}
 
 	counterKey := fmt.Sprintf("%s:%d", consts.RedisSTMLengthKey, page.UserID)
-	cacheKey := getUserSTMCacheKey(page.UserID)
+	cacheKey := getConversationSTMCacheKey(page.UserID, page.ConversationID)
 
 	pipe := r.redisClient.Pipeline()
 	pipe.IncrBy(ctx, counterKey, 1)
@@ -111,6 +115,9 @@ func (r *mateRepo) GetUserPages(ctx context.Context, req *models.GetUserPagesReq
 
 	var pages []*models.Page
 	query := r.pg.WithContext(ctx).Where("user_id = ?", req.UserID).Order("created_at DESC, id DESC")
+	if req.ConversationID != 0 {
+		query = query.Where("conversation_id = ?", req.ConversationID)
+	}
 
 	if req.Cursor != "" {
 		cursorData, err := r.decodeCursor(req.Cursor)
@@ -0,0 +1,63 @@
+package data
+
+import (
+	"github.com/Fl0rencess720/Doria/src/services/mate/internal/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// legacyConversationTitle names the conversation that collects a user's
+// pages from before conversations existed.
+const legacyConversationTitle = "历史对话"
+
+// migrate brings the tables the mate service owns up to date with its models.
+// AutoMigrate only adds tables, columns, indexes and constraints, so it is
+// safe to run on every start. Pages, including the conversation_id and
+// truncated columns, are migrated by the memory service, which owns them.
+func migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&models.Conversation{}); err != nil {
+		return err
+	}
+	return backfillLegacyPages(db)
+}
+
+// backfillLegacyPages moves pages without a conversation into one conversation
+// per user, so they show up in its history and short-term memory again. It
+// waits for the memory service to add the conversation_id column; until then
+// it is skipped and runs on the next start.
+func backfillLegacyPages(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&models.Page{}, "conversation_id") {
+		zap.L().Warn("pages.conversation_id missing, skipping legacy page backfill")
+		return nil
+	}
+
+	legacy := "conversation_id IS NULL OR conversation_id = 0"
+
+	var userIDs []uint
+	if err := db.Model(&models.Page{}).Where(legacy).Distinct().Pluck("user_id", &userIDs).Error; err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			conversation := &models.Conversation{
+				UserID: userID,
+				Title:  legacyConversationTitle,
+			}
+			if err := tx.Create(conversation).Error; err != nil {
+				return err
+			}
+			return tx.Model(&models.Page{}).
+				Where("user_id = ?", userID).Where(legacy).
+				Update("conversation_id", conversation.ID).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(userIDs) > 0 {
+		zap.L().Info("backfilled legacy pages into conversations", zap.Int("users", len(userIDs)))
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package data
+
+import (
+	"context"
+
+	"github.com/Fl0rencess720/Doria/src/common/metering"
+	"github.com/Fl0rencess720/Doria/src/services/mate/internal/models"
+)
+
+func (r *mateRepo) PurgeUserData(ctx context.Context, userID uint) (*models.PurgeResult, error) {
+	var usageKeys []string
+	iter := r.redisClient.Scan(ctx, 0, metering.UserKeyPattern(userID), 100).Iterator()
+	for iter.Next(ctx) {
+		usageKeys = append(usageKeys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &models.PurgeResult{}
+	if len(usageKeys) > 0 {
+		deleted, err := r.redisClient.Del(ctx, usageKeys...).Result()
+		if err != nil {
+			return nil, err
+		}
+		result.UsageKeys = deleted
+	}
+
+	conversations := r.pg.WithContext(ctx).Where("user_id = ?", userID).Delete(&models.Conversation{})
+	if conversations.Error != nil {
+		return nil, conversations.Error
+	}
+	result.Conversations = conversations.RowsAffected
+	return result, nil
+}
@@ -14,14 +14,26 @@ type User struct {
 	Pages     []*Page   `gorm:"foreignKey:UserID"`
 }
 
+type Conversation struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"index;not null"`
+	Title     string    `gorm:"type:text;not null"`
+	Archived  bool      `gorm:"not null;default:false"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	Pages     []*Page   `gorm:"foreignKey:ConversationID"`
+}
+
 type Page struct {
-	ID          uint      `gorm:"primaryKey"`
-	UserID      uint      `gorm:"index;not null"`
-	SegmentID   uint      `gorm:"index"`
-	UserInput   string    `gorm:"type:text"`
-	AgentOutput string    `gorm:"type:text"`
-	Status      string    `gorm:"type:text;not null;check:status IN ('in_stm','in_mtm','invalid')"`
-	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	ID             uint      `gorm:"primaryKey"`
+	UserID         uint      `gorm:"index;not null"`
+	ConversationID uint      `gorm:"index"`
+	SegmentID      uint      `gorm:"index"`
+	UserInput      string    `gorm:"type:text"`
+	AgentOutput    string    `gorm:"type:text"`
+	Truncated      bool      `gorm:"not null;default:false"`
+	Status         string    `gorm:"type:text;not null;check:status IN ('in_stm','in_mtm','invalid')"`
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
 }
 
 type MateMessage struct {
@@ -29,9 +41,10 @@ type MateMessage struct {
 }
 
 type GetUserPagesRequest struct {
-	UserID   uint
-	Cursor   string
-	PageSize int
+	UserID         uint
+	ConversationID uint
+	Cursor         string
+	PageSize       int
 }
 
 type GetUserPagesResponse struct {
@@ -40,7 +53,20 @@ type GetUserPagesResponse struct {
 	HasMore   bool
 }
 
+type GetMessagesRequest struct {
+	UserID         uint
+	ConversationID uint
+	StartTime      int64
+	EndTime        int64
+	Limit          int
+}
+
 type CursorData struct {
 	ID        uint      `json:"id"`
 	CreatedAt time.Time `json:"created_at"`
 }
+
+type PurgeResult struct {
+	Conversations int64
+	UsageKeys     int64
+}
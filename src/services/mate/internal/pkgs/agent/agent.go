@@ -15,9 +15,18 @@ type Agent struct {
 	guidelines []*Guideline
 }
 
+// Persona is the only persona this agent speaks as.
+const Persona = "doria"
+
 type AgentMemory struct {
 	QAparis    []*models.Page
 	Knowledges []string
+	Profile    *Profile
+	// History, when set, is used instead of QAparis as the turns leading up
+	// to the prompt.
+	History []*schema.Message
+	// Instructions are extra system instructions supplied by the caller.
+	Instructions string
 }
 
 func NewAgent(ctx context.Context) (*Agent, error) {
@@ -50,16 +59,18 @@ func NewAgent(ctx context.Context) (*Agent, error) {
 	}, nil
 }
 
-func (a *Agent) Chat(ctx context.Context, memory *AgentMemory, prompt string) (*schema.Message, error) {
-	history := pages2History(memory.QAparis)
+func (a *Agent) Chat(ctx context.Context, memory *AgentMemory, prompt string, opts ...compose.Option) (*schema.Message, error) {
+	history := memory.history()
 	knowledge := formatKnowledges(memory.Knowledges)
 	response, err := a.runnable.Invoke(ctx, map[string]any{
 		"prompt":       prompt,
 		"knowledge":    knowledge,
+		"profile":      formatProfile(memory.Profile),
+		"instructions": formatInstructions(memory.Instructions),
 		"guidelines":   a.guidelines,
 		"history":      history,
 		"tools_output": "",
-	})
+	}, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -67,17 +78,19 @@ func (a *Agent) Chat(ctx context.Context, memory *AgentMemory, prompt string) (*
 	return response, nil
 }
 
-func (a *Agent) ChatStream(ctx context.Context, memory *AgentMemory, prompt string) (*schema.StreamReader[string], error) {
-	history := pages2History(memory.QAparis)
+func (a *Agent) ChatStream(ctx context.Context, memory *AgentMemory, prompt string, opts ...compose.Option) (*schema.StreamReader[string], error) {
+	history := memory.history()
 	knowledge := formatKnowledges(memory.Knowledges)
 
 	outStream, err := a.runnable.Stream(ctx, map[string]any{
 		"prompt":       prompt,
 		"knowledge":    knowledge,
+		"profile":      formatProfile(memory.Profile),
+		"instructions": formatInstructions(memory.Instructions),
 		"guidelines":   a.guidelines,
 		"history":      history,
 		"tools_output": "",
-	})
+	}, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -106,6 +119,13 @@ func (a *Agent) ChatStream(ctx context.Context, memory *AgentMemory, prompt stri
 	return stringReader, nil
 }
 
+func (m *AgentMemory) history() []*schema.Message {
+	if len(m.History) > 0 {
+		return m.History
+	}
+	return pages2History(m.QAparis)
+}
+
 func pages2History(pages []*models.Page) []*schema.Message {
 	history := make([]*schema.Message, 0, len(pages))
 	for _, page := range pages {
@@ -133,3 +153,10 @@ func formatKnowledges(knowledges []string) string {
 
 	return builder.String()
 }
+
+func formatInstructions(instructions string) string {
+	if strings.TrimSpace(instructions) == "" {
+		return "暂无"
+	}
+	return instructions
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 
+	"github.com/Fl0rencess720/Doria/src/common/metrics"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/compose"
@@ -33,6 +34,9 @@ type state struct {
 	history   []*schema.Message
 	prompt    string
 	knowledge string
+	profile   string
+
+	instructions string
 
 	guidelines       []*Guideline
 	guidelinesString string
@@ -41,7 +45,8 @@ type state struct {
 	activeGuidelinesString string
 	toolOutput             string
 
-	epoch int
+	epoch      int
+	iterations int
 }
 
 type InputPayload struct {
@@ -72,10 +77,10 @@ func buildChatGraph(_ context.Context, mainCM model.ToolCallingChatModel,
 	_ = g.AddChatTemplateNode(ObserverPomptTplKey, observerTpl)
 	_ = g.AddChatTemplateNode(DoriaPromptTplKey, doriaTpl)
 
-	_ = g.AddChatModelNode(GuidelineProposerChatModelKey, jsonCM)
-	_ = g.AddChatModelNode(ToolCallerChatModelKey, jsonCM)
-	_ = g.AddChatModelNode(ObserverChatModelKey, jsonCM)
-	_ = g.AddChatModelNode(DoriaChatModelKey, mainCM)
+	_ = g.AddChatModelNode(GuidelineProposerChatModelKey, jsonCM, compose.WithNodeName(GuidelineProposerChatModelKey))
+	_ = g.AddChatModelNode(ToolCallerChatModelKey, jsonCM, compose.WithNodeName(ToolCallerChatModelKey))
+	_ = g.AddChatModelNode(ObserverChatModelKey, jsonCM, compose.WithNodeName(ObserverChatModelKey))
+	_ = g.AddChatModelNode(DoriaChatModelKey, mainCM, compose.WithNodeName(DoriaChatModelKey))
 
 	_ = g.AddLambdaNode(ActiveGuidelinesLambdaKey, compose.InvokableLambda(activeGuidelinesLambda))
 	_ = g.AddLambdaNode(ToolCallingLambdaKey, compose.InvokableLambda(toolCallingLambda))
@@ -108,6 +113,8 @@ func buildChatGraph(_ context.Context, mainCM model.ToolCallingChatModel,
 }
 
 func saveInputToState(ctx context.Context, input map[string]any, state *state) (map[string]any, error) {
+	state.iterations++
+
 	if p, ok := input["prompt"].(string); ok {
 		state.prompt = p
 	}
@@ -117,6 +124,12 @@ func saveInputToState(ctx context.Context, input map[string]any, state *state) (
 	if h, ok := input["knowledge"].(string); ok {
 		state.knowledge = h
 	}
+	if p, ok := input["profile"].(string); ok {
+		state.profile = p
+	}
+	if i, ok := input["instructions"].(string); ok {
+		state.instructions = i
+	}
 	if g, ok := input["guidelines"].([]*Guideline); ok {
 		state.guidelines = g
 		state.guidelinesString = FormatGuidelines(g)
@@ -128,10 +141,12 @@ func saveInputToState(ctx context.Context, input map[string]any, state *state) (
 
 func activeGuidelinesLambda(ctx context.Context, input *schema.Message) (map[string]any, error) {
 	var (
-		history    []*schema.Message
-		prompt     string
-		knowledge  string
-		guidelines []*Guideline
+		history      []*schema.Message
+		prompt       string
+		knowledge    string
+		profile      string
+		instructions string
+		guidelines   []*Guideline
 	)
 
 	if err := compose.ProcessState(ctx, func(ctx context.Context, state *state) error {
@@ -139,6 +154,8 @@ func activeGuidelinesLambda(ctx context.Context, input *schema.Message) (map[str
 		prompt = state.prompt
 		guidelines = state.guidelines
 		knowledge = state.knowledge
+		profile = state.profile
+		instructions = state.instructions
 		return nil
 	}); err != nil {
 		return nil, err
@@ -163,6 +180,8 @@ func activeGuidelinesLambda(ctx context.Context, input *schema.Message) (map[str
 			"history":           history,
 			"prompt":            prompt,
 			"knowledge":         knowledge,
+			"profile":           profile,
+			"instructions":      instructions,
 			"active_guidelines": activeGuidelines,
 			"has_tool":          false,
 		}, nil
@@ -180,6 +199,8 @@ func activeGuidelinesLambda(ctx context.Context, input *schema.Message) (map[str
 			"history":           history,
 			"prompt":            prompt,
 			"knowledge":         knowledge,
+			"profile":           profile,
+			"instructions":      instructions,
 			"active_guidelines": activeGuidelines,
 			"has_tool":          false,
 		}, nil
@@ -229,6 +250,8 @@ func activeGuidelinesLambda(ctx context.Context, input *schema.Message) (map[str
 		"history":           history,
 		"prompt":            prompt,
 		"knowledge":         knowledge,
+		"profile":           profile,
+		"instructions":      instructions,
 		"active_guidelines": activeGuidelinesString,
 		"tools_info":        toolsInfo,
 		"has_tool":          hasTool,
@@ -296,6 +319,8 @@ func convertObserverOutputLambda(ctx context.Context, input *schema.Message) (ma
 		history                []*schema.Message
 		prompt                 string
 		knowledge              string
+		profile                string
+		instructions           string
 		activeGuidelinesString string
 		guidelinesString       string
 		toolsOutput            string
@@ -306,6 +331,8 @@ func convertObserverOutputLambda(ctx context.Context, input *schema.Message) (ma
 		guidelinesString = state.guidelinesString
 		prompt = state.prompt
 		knowledge = state.knowledge
+		profile = state.profile
+		instructions = state.instructions
 		history = state.history
 		toolsOutput = state.toolOutput
 		return nil
@@ -328,6 +355,8 @@ func convertObserverOutputLambda(ctx context.Context, input *schema.Message) (ma
 		"history":           history,
 		"prompt":            prompt,
 		"knowledge":         knowledge,
+		"profile":           profile,
+		"instructions":      instructions,
 		"active_guidelines": activeGuidelinesString,
 		"guidelines":        guidelinesString,
 		"toward":            observerOutput.Toward,
@@ -350,6 +379,7 @@ func toolCallingDecisionBranch(ctx context.Context, input map[string]any) (endNo
 			return compose.END, err
 		}
 
+		observeGuidelineIterations(ctx)
 		if epoch >= 2 {
 			return DoriaPromptTplKey, nil
 		}
@@ -366,5 +396,15 @@ func observerDecisionBranch(ctx context.Context, input map[string]any) (endNode
 		input["tools_output"] = fmt.Sprintf("为了达成用户的要求，曾经调用了工具，工具的输出结果为：\n%s\n但是并不能解决用户的问题，你需要重新进行对用户的问题进行评估\n", input["tools_output"])
 		return GuidelineProposerPromptTplKey, nil
 	}
+	observeGuidelineIterations(ctx)
 	return DoriaPromptTplKey, nil
 }
+
+// observeGuidelineIterations records how many times the guideline proposer
+// ran before the graph settled on an answer.
+func observeGuidelineIterations(ctx context.Context) {
+	_ = compose.ProcessState(ctx, func(ctx context.Context, state *state) error {
+		metrics.GuidelineLoopIterations.Observe(float64(state.iterations))
+		return nil
+	})
+}
@@ -0,0 +1,42 @@
+package agent
+
+import (
+	"strings"
+	"time"
+)
+
+// Profile is the part of the user's profile that shapes how Doria talks to
+// them. A nil or empty profile leaves the prompt without personal details.
+type Profile struct {
+	Nickname  string
+	AddressAs string
+	Timezone  string
+	Locale    string
+}
+
+func formatProfile(profile *Profile) string {
+	if profile == nil {
+		return "暂无"
+	}
+
+	var builder strings.Builder
+	if profile.Nickname != "" {
+		builder.WriteString("- 用户的昵称：" + profile.Nickname + "\n")
+	}
+	if profile.AddressAs != "" {
+		builder.WriteString("- 请称呼用户为：" + profile.AddressAs + "\n")
+	}
+	if profile.Locale != "" {
+		builder.WriteString("- 用户偏好的语言：" + profile.Locale + "\n")
+	}
+	if profile.Timezone != "" {
+		if loc, err := time.LoadLocation(profile.Timezone); err == nil {
+			builder.WriteString("- 用户当地时间：" + time.Now().In(loc).Format("2006-01-02 15:04") + "（" + profile.Timezone + "）\n")
+		}
+	}
+
+	if builder.Len() == 0 {
+		return "暂无"
+	}
+	return builder.String()
+}
@@ -7,6 +7,7 @@ import (
 	"math"
 	"strings"
 
+	"github.com/Fl0rencess720/Doria/src/common/metrics"
 	"github.com/cloudwego/eino/components/tool"
 )
 
@@ -102,6 +103,8 @@ func ExecuteTool(ctx context.Context, tools []tool.BaseTool, toolEval *ToolEvalu
 	}
 
 	if targetTool == nil {
+		// Unknown names come straight from the model, so keep them out of the labels.
+		metrics.ToolCalls.WithLabelValues("unknown", metrics.ToolOutcomeNotFound).Inc()
 		return "", fmt.Errorf("未找到工具: %s", toolEval.ToolName)
 	}
 
@@ -114,12 +117,20 @@ func ExecuteTool(ctx context.Context, tools []tool.BaseTool, toolEval *ToolEvalu
 
 	paramsJSON, err := json.Marshal(params)
 	if err != nil {
+		metrics.ToolCalls.WithLabelValues(toolEval.ToolName, metrics.ToolOutcomeError).Inc()
 		return "", fmt.Errorf("参数序列化失败: %w", err)
 	}
 
 	if invokable, ok := targetTool.(tool.InvokableTool); ok {
-		return invokable.InvokableRun(ctx, string(paramsJSON))
+		output, err := invokable.InvokableRun(ctx, string(paramsJSON))
+		if err != nil {
+			metrics.ToolCalls.WithLabelValues(toolEval.ToolName, metrics.ToolOutcomeError).Inc()
+			return "", err
+		}
+		metrics.ToolCalls.WithLabelValues(toolEval.ToolName, metrics.ToolOutcomeSuccess).Inc()
+		return output, nil
 	}
 
+	metrics.ToolCalls.WithLabelValues(toolEval.ToolName, metrics.ToolOutcomeUnsupported).Inc()
 	return "", fmt.Errorf("工具 %s 不支持调用", toolEval.ToolName)
 }
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+
+	mateapi "github.com/Fl0rencess720/Doria/src/rpc/mate"
+	"github.com/Fl0rencess720/Doria/src/services/mate/internal/models"
+)
+
+func (s *MateService) CreateConversation(ctx context.Context, req *mateapi.CreateConversationRequest) (*mateapi.CreateConversationResponse, error) {
+	conversation, err := s.mateUseCase.CreateConversation(ctx, uint(req.UserId), req.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mateapi.CreateConversationResponse{
+		Conversation: toConversationProto(conversation),
+	}, nil
+}
+
+func (s *MateService) ListConversations(ctx context.Context, req *mateapi.ListConversationsRequest) (*mateapi.ListConversationsResponse, error) {
+	conversations, err := s.mateUseCase.ListConversations(ctx, uint(req.UserId), req.IncludeArchived)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &mateapi.ListConversationsResponse{
+		Conversations: make([]*mateapi.Conversation, len(conversations)),
+	}
+	for i, conversation := range conversations {
+		resp.Conversations[i] = toConversationProto(conversation)
+	}
+
+	return resp, nil
+}
+
+func (s *MateService) RenameConversation(ctx context.Context, req *mateapi.RenameConversationRequest) (*mateapi.RenameConversationResponse, error) {
+	conversation, err := s.mateUseCase.RenameConversation(ctx, uint(req.UserId), uint(req.ConversationId), req.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mateapi.RenameConversationResponse{
+		Conversation: toConversationProto(conversation),
+	}, nil
+}
+
+func (s *MateService) ArchiveConversation(ctx context.Context, req *mateapi.ArchiveConversationRequest) (*mateapi.ArchiveConversationResponse, error) {
+	conversation, err := s.mateUseCase.ArchiveConversation(ctx, uint(req.UserId), uint(req.ConversationId), req.Archived)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mateapi.ArchiveConversationResponse{
+		Conversation: toConversationProto(conversation),
+	}, nil
+}
+
+func (s *MateService) DeleteConversation(ctx context.Context, req *mateapi.DeleteConversationRequest) (*mateapi.DeleteConversationResponse, error) {
+	if err := s.mateUseCase.DeleteConversation(ctx, uint(req.UserId), uint(req.ConversationId)); err != nil {
+		return nil, err
+	}
+
+	return &mateapi.DeleteConversationResponse{}, nil
+}
+
+func toConversationProto(conversation *models.Conversation) *mateapi.Conversation {
+	return &mateapi.Conversation{
+		Id:         uint32(conversation.ID),
+		UserId:     uint32(conversation.UserID),
+		Title:      conversation.Title,
+		Archived:   conversation.Archived,
+		CreateTime: conversation.CreatedAt.Unix(),
+		UpdateTime: conversation.UpdatedAt.Unix(),
+	}
+}
+
+func (s *MateService) PurgeUserData(ctx context.Context, req *mateapi.PurgeUserDataRequest) (*mateapi.PurgeUserDataResponse, error) {
+	result, err := s.mateUseCase.PurgeUserData(ctx, uint(req.UserId))
+	if err != nil {
+		return nil, err
+	}
+
+	return &mateapi.PurgeUserDataResponse{
+		Conversations: result.Conversations,
+		UsageKeys:     result.UsageKeys,
+	}, nil
+}
@@ -5,44 +5,69 @@ import (
 	"io"
 	"time"
 
+	"github.com/Fl0rencess720/Doria/src/common/metering"
+	"github.com/Fl0rencess720/Doria/src/common/rpcerr"
+	"github.com/Fl0rencess720/Doria/src/common/shutdown"
 	mateapi "github.com/Fl0rencess720/Doria/src/rpc/mate"
 	"github.com/Fl0rencess720/Doria/src/services/mate/internal/biz"
 	"github.com/Fl0rencess720/Doria/src/services/mate/internal/models"
+	"github.com/cloudwego/eino/schema"
 )
 
+// usageWaitTimeout bounds how long the finished message waits for usage
+// still being read from the model's stream.
+const usageWaitTimeout = 2 * time.Second
+
 func (s *MateService) Chat(ctx context.Context, req *mateapi.ChatRequest) (*mateapi.ChatResponse, error) {
-	resp, err := s.mateUseCase.Chat(ctx, &biz.ChatReq{
-		UserID: uint(req.UserId),
-		Prompt: req.Prompt,
-	})
+	chatReq, err := newChatReq(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, usage, err := s.mateUseCase.Chat(ctx, chatReq)
 	if err != nil {
 		return nil, err
 	}
 
 	return &mateapi.ChatResponse{
 		Message: resp,
+		Usage:   toUsage(usage),
 	}, nil
 }
 
 func (s *MateService) ChatStream(req *mateapi.ChatRequest, stream mateapi.MateService_ChatStreamServer) error {
+	done, ok := s.shutdown.Track()
+	if !ok {
+		return rpcerr.New(rpcerr.ReasonDegraded, shutdown.ErrDraining.Error())
+	}
+	defer done()
+
 	ctx := stream.Context()
 
-	responseStream, messageID, err := s.mateUseCase.ChatStream(ctx, &biz.ChatReq{
-		UserID: uint(req.UserId),
-		Prompt: req.Prompt,
-	})
+	chatReq, err := newChatReq(req)
 	if err != nil {
 		return err
 	}
 
+	responseStream, messageID, tally, err := s.mateUseCase.ChatStream(ctx, chatReq)
+	if err != nil {
+		return err
+	}
+	defer responseStream.Close()
+
 	for {
 		chunk, err := responseStream.Recv()
 		if err == io.EOF {
+			usageCtx, cancel := context.WithTimeout(ctx, usageWaitTimeout)
+			usage := tally.Usage(usageCtx)
+			cancel()
+
 			return stream.Send(&mateapi.ChatStreamResponse{
 				Content:   "",
 				MessageId: messageID,
 				Timestamp: time.Now().Unix(),
 				Finished:  true,
+				Usage:     toUsage(usage),
 			})
 		}
 		if err != nil {
@@ -62,9 +87,10 @@ func (s *MateService) ChatStream(req *mateapi.ChatRequest, stream mateapi.MateSe
 
 func (s *MateService) GetUserPages(ctx context.Context, req *mateapi.GetUserPagesRequest) (*mateapi.GetUserPagesResponse, error) {
 	pagesResp, err := s.mateUseCase.GetUserPages(ctx, &models.GetUserPagesRequest{
-		UserID:   uint(req.UserId),
-		Cursor:   req.Cursor,
-		PageSize: int(req.PageSize),
+		UserID:         uint(req.UserId),
+		ConversationID: uint(req.ConversationId),
+		Cursor:         req.Cursor,
+		PageSize:       int(req.PageSize),
 	})
 	if err != nil {
 		return nil, err
@@ -78,15 +104,73 @@ func (s *MateService) GetUserPages(ctx context.Context, req *mateapi.GetUserPage
 
 	for i, page := range pagesResp.Pages {
 		resp.Pages[i] = &mateapi.Page{
-			Id:          uint32(page.ID),
-			UserId:      uint32(page.UserID),
-			SegmentId:   uint32(page.SegmentID),
-			UserInput:   page.UserInput,
-			AgentOutput: page.AgentOutput,
-			Status:      page.Status,
-			CreateTime:  page.CreatedAt.Unix(),
+			Id:             uint32(page.ID),
+			UserId:         uint32(page.UserID),
+			ConversationId: uint32(page.ConversationID),
+			SegmentId:      uint32(page.SegmentID),
+			UserInput:      page.UserInput,
+			AgentOutput:    page.AgentOutput,
+			Status:         page.Status,
+			Truncated:      page.Truncated,
+			CreateTime:     page.CreatedAt.Unix(),
 		}
 	}
 
 	return resp, nil
 }
+
+func (s *MateService) GetConversationMessages(ctx context.Context, req *mateapi.GetConversationMessagesRequest) (*mateapi.GetConversationMessagesResponse, error) {
+	messages, err := s.mateUseCase.GetConversationMessages(ctx, &models.GetMessagesRequest{
+		UserID:         uint(req.UserId),
+		ConversationID: uint(req.ConversationId),
+		StartTime:      req.StartTime,
+		EndTime:        req.EndTime,
+		Limit:          int(req.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &mateapi.GetConversationMessagesResponse{
+		Messages: make([]*mateapi.Message, len(messages)),
+	}
+	for i, message := range messages {
+		resp.Messages[i] = &mateapi.Message{
+			Role:       message.Role,
+			Content:    message.Content,
+			CreateTime: message.CreateTime,
+		}
+	}
+
+	return resp, nil
+}
+
+// newChatReq converts a chat request, rejecting history turns that are
+// neither the user's nor the assistant's.
+func newChatReq(req *mateapi.ChatRequest) (*biz.ChatReq, error) {
+	history := make([]*schema.Message, 0, len(req.History))
+	for _, message := range req.History {
+		role := schema.RoleType(message.Role)
+		if role != schema.User && role != schema.Assistant {
+			return nil, rpcerr.New(rpcerr.ReasonValidation, "unsupported history role: "+message.Role)
+		}
+		history = append(history, &schema.Message{Role: role, Content: message.Content})
+	}
+
+	return &biz.ChatReq{
+		UserID:         uint(req.UserId),
+		ConversationID: uint(req.ConversationId),
+		Prompt:         req.Prompt,
+		Persona:        req.Persona,
+		Instructions:   req.Instructions,
+		History:        history,
+	}, nil
+}
+
+func toUsage(usage metering.Usage) *mateapi.Usage {
+	return &mateapi.Usage{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
@@ -8,7 +9,11 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/Fl0rencess720/Doria/src/common/health"
+	"github.com/Fl0rencess720/Doria/src/common/metrics"
 	"github.com/Fl0rencess720/Doria/src/common/registry"
+	"github.com/Fl0rencess720/Doria/src/common/rpcerr"
+	"github.com/Fl0rencess720/Doria/src/common/shutdown"
 	mateapi "github.com/Fl0rencess720/Doria/src/rpc/mate"
 	"github.com/Fl0rencess720/Doria/src/services/mate/internal/biz"
 	"github.com/google/wire"
@@ -19,7 +24,7 @@ import (
 	"google.golang.org/grpc/keepalive"
 )
 
-var ProviderSet = wire.NewSet(NewMateService)
+var ProviderSet = wire.NewSet(NewMateService, shutdown.NewCoordinator)
 
 type MateService struct {
 	mateapi.UnimplementedMateServiceServer
@@ -28,11 +33,13 @@ type MateService struct {
 	registry    *registry.RegistrationManager
 	server      *grpc.Server
 	listener    net.Listener
+	health      *health.Checker
+	shutdown    *shutdown.Coordinator
 
 	mateUseCase *biz.MateUseCase
 }
 
-func NewMateService(serviceName string, mateUseCase *biz.MateUseCase) *MateService {
+func NewMateService(serviceName string, mateUseCase *biz.MateUseCase, checker *health.Checker, coordinator *shutdown.Coordinator) *MateService {
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", viper.GetInt("server.grpc.port")))
 	if err != nil {
 		panic(err)
@@ -51,6 +58,8 @@ func NewMateService(serviceName string, mateUseCase *biz.MateUseCase) *MateServi
 	server := grpc.NewServer(
 		grpc.KeepaliveEnforcementPolicy(kaep),
 		grpc.KeepaliveParams(kasp),
+		grpc.ChainUnaryInterceptor(metrics.UnaryServerInterceptor(), rpcerr.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(metrics.StreamServerInterceptor(), rpcerr.StreamServerInterceptor()),
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 	)
 
@@ -61,10 +70,13 @@ func NewMateService(serviceName string, mateUseCase *biz.MateUseCase) *MateServi
 		registry:    registrationManager,
 		server:      server,
 		listener:    lis,
+		health:      checker,
+		shutdown:    coordinator,
 		mateUseCase: mateUseCase,
 	}
 
 	mateapi.RegisterMateServiceServer(server, s)
+	checker.Register(server)
 
 	return s
 }
@@ -76,7 +88,8 @@ func (s *MateService) Start() error {
 	}
 	s.serviceID = serviceID
 
-	go s.registry.SetTTLHealthCheck()
+	s.health.Start(context.Background())
+	go s.registry.SetTTLHealthCheck(s.health.Err)
 
 	go func() {
 		if err := s.server.Serve(s.listener); err != nil {
@@ -94,6 +107,17 @@ func (s *MateService) Stop() error {
 				zap.Error(err))
 		}
 	}
+	s.health.Shutdown()
+
+	// Let running chat streams finish before stopping; GracefulStop alone
+	// would wait for them without any bound.
+	zap.L().Info("Draining chat streams...")
+	if err := s.shutdown.Drain(context.Background()); err != nil {
+		zap.L().Warn("grace period expired before streams drained", zap.Error(err))
+		s.server.Stop()
+		return nil
+	}
+
 	zap.L().Info("Shutting down gRPC server...")
 	s.server.GracefulStop()
 	return nil
@@ -7,6 +7,7 @@
 package main
 
 import (
+	"github.com/Fl0rencess720/Doria/src/common/metering"
 	"github.com/Fl0rencess720/Doria/src/services/memory/configs"
 	"github.com/Fl0rencess720/Doria/src/services/memory/internal/biz"
 	"github.com/Fl0rencess720/Doria/src/services/memory/internal/data"
@@ -27,9 +28,11 @@ func wireApp() *App {
 	embedder := rag.NewEmbedder()
 	memoryRetriever := data.NewMemoryRetriever(embedder)
 	memoryRepo := data.NewMemoryRepo(kafkaClient, db, client, locker, memoryRetriever)
-	llmAgent := agent.NewAgent()
+	recorder := metering.NewRedisRecorder(client)
+	llmAgent := agent.NewAgent(recorder)
 	memoryUseCase := biz.NewMemoryUseCase(memoryRepo, llmAgent)
-	memoryService := service.NewMemoryService(string2, memoryUseCase)
+	checker := data.NewHealthChecker(db, client, memoryRetriever)
+	memoryService := service.NewMemoryService(string2, memoryUseCase, checker)
 	ragRepo := data.NewRAGRepo(embedder)
 	ragUseCase := biz.NewRAGUseCase(ragRepo)
 	ragmcpService := service.NewRAGMCPServer(ragUseCase)
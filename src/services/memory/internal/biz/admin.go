@@ -0,0 +1,67 @@
+package biz
+
+import (
+	"context"
+	"time"
+
+	"github.com/Fl0rencess720/Doria/src/services/memory/internal/models"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+const reprocessTimeout = 40 * time.Second
+
+type MemoryTiers struct {
+	ShortTerm []*models.Page
+	MidTerm   []*models.Segment
+	LongTerm  []*models.LongTermMemory
+}
+
+// GetMemoryTiers reads every tier straight from postgres, bypassing the
+// caches, so operators see what the transitions actually persisted.
+func (uc *MemoryUseCase) GetMemoryTiers(ctx context.Context, userID uint) (*MemoryTiers, error) {
+	tiers := &MemoryTiers{}
+	g, gCtx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		var err error
+		tiers.ShortTerm, err = uc.repo.ListPagesByStatus(gCtx, userID, "in_stm")
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		tiers.MidTerm, err = uc.repo.FindHotSegments(gCtx, userID)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		tiers.LongTerm, err = uc.repo.GetLTM(gCtx, userID)
+		return err
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return tiers, nil
+}
+
+// ReprocessMemory runs the STM -> MTM -> LTM transitions for a user in the
+// background, the same way a new message would trigger them.
+func (uc *MemoryUseCase) ReprocessMemory(userID uint) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), reprocessTimeout)
+		defer cancel()
+
+		err := uc.repo.ProcessWithLock(ctx, userID, func(lockedCtx context.Context) error {
+			return uc.processMemoryTransition(lockedCtx, userID)
+		})
+		if err != nil {
+			zap.L().Error("Failed to reprocess memory for user",
+				zap.Uint("userID", userID),
+				zap.Error(err),
+			)
+			return
+		}
+		zap.L().Info("Memory reprocessed", zap.Uint("userID", userID))
+	}()
+}
@@ -21,7 +21,7 @@ type MemoryRepo interface {
 	IsKnowledgeRedundant(ctx context.Context, userID uint, knowledge string) (bool, error)
 	ArchiveSegmentsToLTM(ctx context.Context, ltmRecords []*models.LongTermMemory, segmentIDsToDel []uint, pageIDsToArchive []uint) error
 
-	GetSTM(ctx context.Context, userID uint) ([]*models.Page, error)
+	GetSTM(ctx context.Context, userID, conversationID uint) ([]*models.Page, error)
 	GetMTM(ctx context.Context, userID uint, prompt string) ([]*models.Page, error)
 	GetLTMFromCache(ctx context.Context, userID uint) ([]*models.LongTermMemory, error)
 	AddPageToSTMCache(ctx context.Context, page *models.Page) error
@@ -29,6 +29,14 @@ type MemoryRepo interface {
 	SaveLTMToCache(ctx context.Context, userID uint, ltmRecords []*models.LongTermMemory) error
 	DeleteLTMFromCache(ctx context.Context, userID uint) error
 	GetLTM(ctx context.Context, userID uint) ([]*models.LongTermMemory, error)
+
+	GetMessagePages(ctx context.Context, req *models.GetMessagesRequest) ([]*models.Page, error)
+	ListPagesByStatus(ctx context.Context, userID uint, status string) ([]*models.Page, error)
+	IsVisitor(ctx context.Context, userID uint) (bool, error)
+	// IsActive reports whether userID exists and is not disabled. Accounts
+	// being deleted are disabled first.
+	IsActive(ctx context.Context, userID uint) (bool, error)
+	PurgeUserMemory(ctx context.Context, userID uint) (*models.PurgeResult, error)
 }
 
 type LLMAgent interface {
@@ -48,6 +56,17 @@ type Memory struct {
 	MemType     uint
 }
 
+type Message struct {
+	Role       string
+	Content    string
+	CreateTime int64
+}
+
+const (
+	defaultMessageLimit = 100
+	maxMessageLimit     = 500
+)
+
 const (
 	QAStatusInSTM = iota
 	QAStatusInMTM
@@ -6,6 +6,7 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/Fl0rencess720/Doria/src/common/metrics"
 	"github.com/Fl0rencess720/Doria/src/services/memory/internal/models"
 	"github.com/Fl0rencess720/Doria/src/services/memory/internal/pkgs/utils"
 	"go.uber.org/zap"
@@ -73,9 +74,31 @@ func (uc *MemoryUseCase) memoryProcessWorker(ctx context.Context, jobChan <-chan
 }
 
 func (uc *MemoryUseCase) processMemoryTransition(ctx context.Context, userID uint) error {
+	// Signals for disabled or deleted accounts are dropped, so pages written
+	// around a deletion never turn into segments, vectors or long-term memory.
+	isActive, err := uc.repo.IsActive(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check user status: %w", err)
+	}
+	if !isActive {
+		zap.L().Info("User is disabled or deleted, dropping memory signal.", zap.Uint("userID", userID))
+		return nil
+	}
+
 	if err := uc.transitionSTMToMTM(ctx, userID); err != nil {
 		return fmt.Errorf("failed during STM to MTM transition: %w", err)
 	}
+
+	// Guests get no long-term memory. Their segments stay in MTM and are
+	// promoted by the first run after they upgrade to a full account.
+	isVisitor, err := uc.repo.IsVisitor(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check user status: %w", err)
+	}
+	if isVisitor {
+		return nil
+	}
+
 	if err := uc.transitionMTMToLTM(ctx, userID); err != nil {
 		return fmt.Errorf("failed during MTM to LTM transition: %w", err)
 	}
@@ -100,6 +123,11 @@ func (uc *MemoryUseCase) transitionSTMToMTM(ctx context.Context, userID uint) er
 		return nil
 	}
 
+	moved := 0
+	defer func() {
+		metrics.MemoryTransitionItems.WithLabelValues(metrics.TransitionSTMToMTM).Observe(float64(moved))
+	}()
+
 	for _, page := range pagesToMove {
 		correlation, err := uc.repo.FindMostRelevantSegment(ctx, userID, page)
 		if err != nil {
@@ -121,6 +149,7 @@ func (uc *MemoryUseCase) transitionSTMToMTM(ctx context.Context, userID uint) er
 				return fmt.Errorf("failed to append page to segment %d: %w", correlation.SegmentID, err)
 			}
 		}
+		moved++
 	}
 	return nil
 }
@@ -188,6 +217,7 @@ func (uc *MemoryUseCase) transitionMTMToLTM(ctx context.Context, userID uint) er
 			return fmt.Errorf("failed to archive segments to LTM: %w", err)
 		}
 	}
+	metrics.MemoryTransitionItems.WithLabelValues(metrics.TransitionMTMToLTM).Observe(float64(len(segmentIDsToDelete)))
 
 	if err := uc.repo.DeleteLTMFromCache(ctx, userID); err != nil {
 		zap.L().Error("Failed to delete LTM from cache", zap.Uint("userID", userID), zap.Error(err))
@@ -196,14 +226,14 @@ func (uc *MemoryUseCase) transitionMTMToLTM(ctx context.Context, userID uint) er
 	return nil
 }
 
-func (uc *MemoryUseCase) RetrieveMemory(ctx context.Context, userID uint, prompt string) ([]*Memory, error) {
+func (uc *MemoryUseCase) RetrieveMemory(ctx context.Context, userID, conversationID uint, prompt string) ([]*Memory, error) {
 	g, gCtx := errgroup.WithContext(ctx)
 	var stmPages, mtmPages []*models.Page
 	var ltm []*models.LongTermMemory
 
 	g.Go(func() error {
 		var err error
-		stmPages, err = uc.repo.GetSTM(gCtx, userID)
+		stmPages, err = uc.repo.GetSTM(gCtx, userID, conversationID)
 		if err != nil {
 			zap.L().Error("get STM pages failed", zap.Error(err))
 			return err
@@ -271,3 +301,58 @@ func (uc *MemoryUseCase) RetrieveMemory(ctx context.Context, userID uint, prompt
 
 	return outputMemory, nil
 }
+
+// GetMessages returns up to req.Limit messages of the newest pages in
+// chronological order. Pages are kept whole, so a transcript never opens
+// with a reply whose prompt was cut off; the newest page is always included.
+func (uc *MemoryUseCase) GetMessages(ctx context.Context, req *models.GetMessagesRequest) ([]*Message, error) {
+	if req.Limit <= 0 {
+		req.Limit = defaultMessageLimit
+	}
+	if req.Limit > maxMessageLimit {
+		req.Limit = maxMessageLimit
+	}
+
+	// A page holds at most two messages, so Limit pages are always enough.
+	pages, err := uc.repo.GetMessagePages(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	start, count := len(pages), 0
+	for start > 0 {
+		n := len(pageMessages(pages[start-1]))
+		if count > 0 && count+n > req.Limit {
+			break
+		}
+		start--
+		count += n
+	}
+
+	messages := make([]*Message, 0, count)
+	for _, page := range pages[start:] {
+		messages = append(messages, pageMessages(page)...)
+	}
+	return messages, nil
+}
+
+func pageMessages(page *models.Page) []*Message {
+	createTime := page.CreatedAt.Unix()
+
+	messages := make([]*Message, 0, 2)
+	if page.UserInput != "" {
+		messages = append(messages, &Message{
+			Role:       "user",
+			Content:    page.UserInput,
+			CreateTime: createTime,
+		})
+	}
+	if page.AgentOutput != "" {
+		messages = append(messages, &Message{
+			Role:       "assistant",
+			Content:    page.AgentOutput,
+			CreateTime: createTime,
+		})
+	}
+	return messages
+}
@@ -0,0 +1,37 @@
+package biz
+
+import (
+	"context"
+
+	"github.com/Fl0rencess720/Doria/src/common/rpcerr"
+	"github.com/Fl0rencess720/Doria/src/services/memory/internal/models"
+	"go.uber.org/zap"
+)
+
+var ErrMemoryBusy = rpcerr.New(rpcerr.ReasonDegraded, "memory is being processed, retry later")
+
+// PurgeUserMemory erases every memory tier a user has. It holds the same lock
+// as the transitions so a late message cannot re-create pages mid-purge, and
+// it is safe to call again after a partial failure.
+func (uc *MemoryUseCase) PurgeUserMemory(ctx context.Context, userID uint) (*models.PurgeResult, error) {
+	var result *models.PurgeResult
+	err := uc.repo.ProcessWithLock(ctx, userID, func(lockedCtx context.Context) error {
+		var err error
+		result, err = uc.repo.PurgeUserMemory(lockedCtx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, ErrMemoryBusy
+	}
+
+	zap.L().Info("User memory purged",
+		zap.Uint("userID", userID),
+		zap.Int64("pages", result.Pages),
+		zap.Int64("segments", result.Segments),
+		zap.Int64("longTermMemories", result.LongTermMemories),
+	)
+	return result, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 
+	"github.com/Fl0rencess720/Doria/src/common/metering"
 	"github.com/Fl0rencess720/Doria/src/services/memory/internal/biz"
 	"github.com/Fl0rencess720/Doria/src/services/memory/internal/models"
 	"github.com/Fl0rencess720/Doria/src/services/memory/internal/pkgs/utils"
@@ -15,9 +16,10 @@ import (
 type agent struct {
 	segmentOverviewRunnable     compose.Runnable[map[string]any, *schema.Message]
 	knowledgeExtractionRunnable compose.Runnable[map[string]any, *schema.Message]
+	usageRecorder               metering.Recorder
 }
 
-func NewAgent() biz.LLMAgent {
+func NewAgent(usageRecorder metering.Recorder) biz.LLMAgent {
 	ctx := context.Background()
 
 	cm, err := newChatModel(ctx)
@@ -48,6 +50,7 @@ func NewAgent() biz.LLMAgent {
 	return &agent{
 		segmentOverviewRunnable:     sr,
 		knowledgeExtractionRunnable: kr,
+		usageRecorder:               usageRecorder,
 	}
 }
 
@@ -63,7 +66,7 @@ func (a *agent) GenSegmentOverview(ctx context.Context, qas []*models.Page) (str
 
 	response, err := a.segmentOverviewRunnable.Invoke(ctx, map[string]any{
 		"qas": qasString,
-	})
+	}, a.usageCallbacks(qas)...)
 	if err != nil {
 		return "", err
 	}
@@ -84,10 +87,17 @@ func (a *agent) GenKnowledgeExtraction(ctx context.Context, qas []*models.Page,
 	response, err := a.knowledgeExtractionRunnable.Invoke(ctx, map[string]any{
 		"qas":       qasString,
 		"knowledge": knowledge,
-	})
+	}, a.usageCallbacks(qas)...)
 	if err != nil {
 		return "", err
 	}
 
 	return response.Content, nil
 }
+
+func (a *agent) usageCallbacks(qas []*models.Page) []compose.Option {
+	if len(qas) == 0 {
+		return nil
+	}
+	return []compose.Option{compose.WithCallbacks(metering.NewHandler(a.usageRecorder, qas[0].UserID))}
+}
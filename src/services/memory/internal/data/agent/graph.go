@@ -22,7 +22,7 @@ func buildSegmentOverviewGraph(_ context.Context, cm model.ToolCallingChatModel)
 
 	g.AddChatTemplateNode(SegmentOverviewTemplateKey, segmentOverviewTpl)
 
-	g.AddChatModelNode(SegmentOverviewChatModelKey, cm)
+	g.AddChatModelNode(SegmentOverviewChatModelKey, cm, compose.WithNodeName(SegmentOverviewChatModelKey))
 
 	g.AddEdge(compose.START, SegmentOverviewTemplateKey)
 	g.AddEdge(SegmentOverviewTemplateKey, SegmentOverviewChatModelKey)
@@ -37,7 +37,7 @@ func buildKnowledgeExtractionGraph(_ context.Context, cm model.ToolCallingChatMo
 
 	g.AddChatTemplateNode(KnowLedgeExtractionTemplateKey, knowledgeExtractionTpl)
 
-	g.AddChatModelNode(KnowLedgeExtractionChatModelKey, cm)
+	g.AddChatModelNode(KnowLedgeExtractionChatModelKey, cm, compose.WithNodeName(KnowLedgeExtractionChatModelKey))
 
 	g.AddEdge(compose.START, KnowLedgeExtractionTemplateKey)
 	g.AddEdge(KnowLedgeExtractionTemplateKey, KnowLedgeExtractionChatModelKey)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/Fl0rencess720/Doria/src/common/metering"
 	"github.com/Fl0rencess720/Doria/src/consts"
 	"github.com/Fl0rencess720/Doria/src/services/memory/internal/data/agent"
 	"github.com/Fl0rencess720/Doria/src/services/memory/internal/data/distlock"
@@ -22,7 +23,7 @@ const consumerGroupID = "memory-service-consumer-group"
 
 var ProviderSet = wire.NewSet(NewMemoryRepo, NewRAGRepo, NewKafkaClient,
 	NewPostgres, NewRedis, NewMemoryRetriever, agent.NewAgent, distlock.NewRedisLocker,
-	rag.NewEmbedder)
+	rag.NewEmbedder, metering.NewRedisRecorder, NewHealthChecker)
 
 type kafkaClient struct {
 	Reader *kafka.Reader
@@ -48,11 +49,15 @@ func NewKafkaClient() *kafkaClient {
 func NewPostgres() *gorm.DB {
 	dsn := fmt.Sprintf(viper.GetString("database.postgres.dsn"), viper.GetString("POSTGRES_HOST"), viper.GetString("POSTGRES_PASSWORD"), viper.GetString("POSTGRES_PORT"))
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		PrepareStmt: true,
+		PrepareStmt:                              true,
+		DisableForeignKeyConstraintWhenMigrating: true,
 	})
 	if err != nil {
 		zap.L().Panic("failed to connect to postgres", zap.Error(err))
 	}
+	if err := migrate(db); err != nil {
+		zap.L().Panic("failed to migrate postgres", zap.Error(err))
+	}
 	return db
 }
 
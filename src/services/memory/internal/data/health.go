@@ -0,0 +1,17 @@
+package data
+
+import (
+	"github.com/Fl0rencess720/Doria/src/common/health"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+func NewHealthChecker(pg *gorm.DB, rdb *redis.Client, retriever *memoryRetriever) *health.Checker {
+	checker := health.NewChecker()
+	checker.Add("postgres", health.Postgres(pg))
+	checker.Add("redis", health.Redis(rdb))
+	checker.Add("milvus", health.Milvus(retriever.client))
+	checker.Add("kafka", health.Kafka(viper.GetString("KAFKA_ADDR")))
+	return checker
+}
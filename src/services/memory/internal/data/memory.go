@@ -6,9 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Fl0rencess720/Doria/src/common/metrics"
 	"github.com/Fl0rencess720/Doria/src/consts"
 	"github.com/Fl0rencess720/Doria/src/services/memory/internal/biz"
 	"github.com/Fl0rencess720/Doria/src/services/memory/internal/data/distlock"
@@ -68,6 +70,10 @@ func (r *memoryRepo) ReadMessage(ctx context.Context) (*models.MateMessage, erro
 	if err != nil {
 		return nil, err
 	}
+	// Reader.Lag is unavailable for consumer groups, so derive it from the
+	// high watermark the fetch returned alongside the message.
+	metrics.KafkaConsumerLag.WithLabelValues(msg.Topic, strconv.Itoa(msg.Partition)).
+		Set(float64(max(msg.HighWaterMark-msg.Offset-1, 0)))
 	mateMessage := models.MateMessage{}
 	if err := json.Unmarshal(msg.Value, &mateMessage); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal kafka message: %w", err)
@@ -267,13 +273,14 @@ func (r *memoryRepo) ArchiveSegmentsToLTM(ctx context.Context, ltmRecords []*mod
 	})
 }
 
-func (r *memoryRepo) GetSTM(ctx context.Context, userID uint) ([]*models.Page, error) {
+func (r *memoryRepo) GetSTM(ctx context.Context, userID, conversationID uint) ([]*models.Page, error) {
 	STMCapacity := viper.GetInt("memory.stm_capacity")
 
-	pagesFromCache, err := r.getSTMFromCache(ctx, userID, STMCapacity)
+	pagesFromCache, err := r.getSTMFromCache(ctx, userID, conversationID, STMCapacity)
 	if err != nil {
 		zap.L().Error("Failed to get STM from cache, falling back to database",
 			zap.Uint("userID", userID),
+			zap.Uint("conversationID", conversationID),
 			zap.Error(err))
 	} else if len(pagesFromCache) > 0 {
 		return pagesFromCache, nil
@@ -282,6 +289,7 @@ func (r *memoryRepo) GetSTM(ctx context.Context, userID uint) ([]*models.Page, e
 	pagesFromDB := []*models.Page{}
 	if err := r.pg.WithContext(ctx).Debug().
 		Where("user_id = ?", userID).
+		Where("conversation_id = ?", conversationID).
 		Where("status = ?", "in_stm").
 		Order("created_at ASC").
 		Find(&pagesFromDB).Error; err != nil {
@@ -289,7 +297,7 @@ func (r *memoryRepo) GetSTM(ctx context.Context, userID uint) ([]*models.Page, e
 	}
 
 	if len(pagesFromDB) > 0 {
-		if err := r.saveSTMToCache(ctx, userID, pagesFromDB); err != nil {
+		if err := r.saveSTMToCache(ctx, userID, conversationID, pagesFromDB); err != nil {
 			zap.L().Error("Failed to cache STM pages",
 				zap.Uint("userID", userID),
 				zap.Int("pageCount", len(pagesFromDB)),
@@ -377,17 +385,81 @@ func (r *memoryRepo) GetLTM(ctx context.Context, userID uint) ([]*models.LongTer
 	return ltms, nil
 }
 
+func (r *memoryRepo) GetMessagePages(ctx context.Context, req *models.GetMessagesRequest) ([]*models.Page, error) {
+	query := r.pg.WithContext(ctx).
+		Where("user_id = ?", req.UserID).
+		Where("status <> ?", "invalid")
+
+	if req.ConversationID != 0 {
+		query = query.Where("conversation_id = ?", req.ConversationID)
+	}
+	if req.StartTime > 0 {
+		query = query.Where("created_at >= ?", time.Unix(req.StartTime, 0))
+	}
+	if req.EndTime > 0 {
+		query = query.Where("created_at <= ?", time.Unix(req.EndTime, 0))
+	}
+
+	pages := []*models.Page{}
+	if err := query.Order("created_at DESC, id DESC").Limit(req.Limit).Find(&pages).Error; err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(pages)-1; i < j; i, j = i+1, j-1 {
+		pages[i], pages[j] = pages[j], pages[i]
+	}
+
+	return pages, nil
+}
+
+func (r *memoryRepo) ListPagesByStatus(ctx context.Context, userID uint, status string) ([]*models.Page, error) {
+	pages := []*models.Page{}
+	if err := r.pg.WithContext(ctx).
+		Where("user_id = ? AND status = ?", userID, status).
+		Order("created_at ASC, id ASC").
+		Find(&pages).Error; err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+func (r *memoryRepo) IsVisitor(ctx context.Context, userID uint) (bool, error) {
+	var count int64
+	if err := r.pg.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND status = ?", userID, "visitor").
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *memoryRepo) IsActive(ctx context.Context, userID uint) (bool, error) {
+	var count int64
+	if err := r.pg.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND disabled = ?", userID, false).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
 
 func getUserLTMKey(userID uint) string {
 	return fmt.Sprintf("ltm:%d", userID)
 }
 
-func getUserSTMCacheKey(userID uint) string {
+func getConversationSTMCacheKey(userID, conversationID uint) string {
+	return fmt.Sprintf("%s:%d:%d", consts.STMPageCachePrefix, userID, conversationID)
+}
+
+// getLegacySTMCacheKey is the per-user STM cache used before STM was scoped
+// to conversations. Nothing writes it any more, but it can outlive an upgrade
+// by up to STMPageCacheTTL.
+func getLegacySTMCacheKey(userID uint) string {
 	return fmt.Sprintf("%s:%d", consts.STMPageCachePrefix, userID)
 }
 
-func (r *memoryRepo) getSTMFromCache(ctx context.Context, userID uint, limit int) ([]*models.Page, error) {
-	cacheKey := getUserSTMCacheKey(userID)
+func (r *memoryRepo) getSTMFromCache(ctx context.Context, userID, conversationID uint, limit int) ([]*models.Page, error) {
+	cacheKey := getConversationSTMCacheKey(userID, conversationID)
 
 	results, err := r.redisClient.ZRevRangeWithScores(ctx, cacheKey, 0, int64(limit-1)).Result()
 	if err != nil {
@@ -410,12 +482,12 @@ func (r *memoryRepo) getSTMFromCache(ctx context.Context, userID uint, limit int
 	return pages, nil
 }
 
-func (r *memoryRepo) saveSTMToCache(ctx context.Context, userID uint, pages []*models.Page) error {
+func (r *memoryRepo) saveSTMToCache(ctx context.Context, userID, conversationID uint, pages []*models.Page) error {
 	if len(pages) == 0 {
 		return nil
 	}
 
-	cacheKey := getUserSTMCacheKey(userID)
+	cacheKey := getConversationSTMCacheKey(userID, conversationID)
 
 	if err := r.redisClient.Del(ctx, cacheKey).Err(); err != nil {
 		return err
@@ -444,7 +516,7 @@ func (r *memoryRepo) saveSTMToCache(ctx context.Context, userID uint, pages []*m
 }
 
 func (r *memoryRepo) AddPageToSTMCache(ctx context.Context, page *models.Page) error {
-	cacheKey := getUserSTMCacheKey(page.UserID)
+	cacheKey := getConversationSTMCacheKey(page.UserID, page.ConversationID)
 
 	jsonData, err := json.Marshal(page)
 	if err != nil {
@@ -463,9 +535,17 @@ func (r *memoryRepo) AddPageToSTMCache(ctx context.Context, page *models.Page) e
 }
 
 func (r *memoryRepo) InvalidateSTMCache(ctx context.Context, userID uint) error {
-	cacheKey := getUserSTMCacheKey(userID)
+	pattern := fmt.Sprintf("%s:%d:*", consts.STMPageCachePrefix, userID)
 
-	err := r.redisClient.Del(ctx, cacheKey).Err()
+	cacheKeys := []string{getLegacySTMCacheKey(userID)}
+	iter := r.redisClient.Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		cacheKeys = append(cacheKeys, iter.Val())
+	}
+	err := iter.Err()
+	if err == nil && len(cacheKeys) > 0 {
+		err = r.redisClient.Del(ctx, cacheKeys...).Err()
+	}
 	if err != nil {
 		zap.L().Error("Failed to invalidate STM cache",
 			zap.Uint("userID", userID),
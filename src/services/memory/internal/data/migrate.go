@@ -0,0 +1,14 @@
+package data
+
+import (
+	"github.com/Fl0rencess720/Doria/src/services/memory/internal/models"
+	"gorm.io/gorm"
+)
+
+// migrate brings the tables the memory service owns up to date with its
+// models. AutoMigrate only adds tables, columns, indexes and constraints, so
+// it is safe to run on every start. Foreign keys are not created since
+// existing pages carry 0 for a missing segment or conversation.
+func migrate(db *gorm.DB) error {
+	return db.AutoMigrate(&models.Page{}, &models.Segment{}, &models.LongTermMemory{})
+}
@@ -0,0 +1,98 @@
+package data
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Fl0rencess720/Doria/src/consts"
+	"github.com/Fl0rencess720/Doria/src/services/memory/internal/models"
+	"github.com/Fl0rencess720/Doria/src/services/memory/internal/pkgs/utils"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+const purgeBatchSize = 1000
+
+// PurgeUserMemory removes vectors before rows: page vectors are keyed by page
+// ID only, so postgres must still hold the IDs if a retry is needed.
+func (r *memoryRepo) PurgeUserMemory(ctx context.Context, userID uint) (*models.PurgeResult, error) {
+	var pageIDs []uint
+	if err := r.pg.WithContext(ctx).Model(&models.Page{}).Where("user_id = ?", userID).Pluck("id", &pageIDs).Error; err != nil {
+		return nil, err
+	}
+	if err := r.deleteUserVectors(ctx, userID, pageIDs); err != nil {
+		return nil, err
+	}
+
+	cacheKeys, err := r.deleteUserCache(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.PurgeResult{CacheKeys: cacheKeys}
+	err = r.pg.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		pages := tx.Where("user_id = ?", userID).Delete(&models.Page{})
+		if pages.Error != nil {
+			return pages.Error
+		}
+		segments := tx.Where("user_id = ?", userID).Delete(&models.Segment{})
+		if segments.Error != nil {
+			return segments.Error
+		}
+		ltms := tx.Where("user_id = ?", userID).Delete(&models.LongTermMemory{})
+		if ltms.Error != nil {
+			return ltms.Error
+		}
+
+		result.Pages = pages.RowsAffected
+		result.Segments = segments.RowsAffected
+		result.LongTermMemories = ltms.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *memoryRepo) deleteUserVectors(ctx context.Context, userID uint, pageIDs []uint) error {
+	client := r.memoryRetriever.client
+	userExpr := fmt.Sprintf("user_id == %d", userID)
+
+	for _, collection := range []string{
+		viper.GetString("memory.milvus.ltm_collection"),
+		viper.GetString("memory.milvus.segment_collection"),
+	} {
+		if _, err := client.Delete(ctx, milvusclient.NewDeleteOption(collection).WithExpr(userExpr)); err != nil {
+			return fmt.Errorf("delete vectors from %s: %w", collection, err)
+		}
+	}
+
+	pageCollection := viper.GetString("memory.milvus.page_collection")
+	for start := 0; start < len(pageIDs); start += purgeBatchSize {
+		end := min(start+purgeBatchSize, len(pageIDs))
+		_, err := client.Delete(ctx, milvusclient.NewDeleteOption(pageCollection).
+			WithInt64IDs("page_id", utils.ConvertUintToInt64(pageIDs[start:end])))
+		if err != nil {
+			return fmt.Errorf("delete vectors from %s: %w", pageCollection, err)
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepo) deleteUserCache(ctx context.Context, userID uint) (int64, error) {
+	cacheKeys := []string{
+		fmt.Sprintf("%s:%d", consts.RedisSTMLengthKey, userID),
+		getUserLTMKey(userID),
+		getLegacySTMCacheKey(userID),
+	}
+	iter := r.redisClient.Scan(ctx, 0, fmt.Sprintf("%s:%d:*", consts.STMPageCachePrefix, userID), 100).Iterator()
+	for iter.Next(ctx) {
+		cacheKeys = append(cacheKeys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return 0, err
+	}
+	return r.redisClient.Del(ctx, cacheKeys...).Result()
+}
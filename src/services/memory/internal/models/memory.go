@@ -8,19 +8,22 @@ type User struct {
 	Status    string     `gorm:"type:text;not null;check:status IN ('user','visitor')"`
 	Phone     *string    `gorm:"type:text;unique"`
 	Password  string     `gorm:"type:text;not null"`
+	Disabled  bool       `gorm:"not null;default:false"`
 	CreatedAt time.Time  `gorm:"autoCreateTime"`
 	Pages     []*Page    `gorm:"foreignKey:UserID"`
 	Segments  []*Segment `gorm:"foreignKey:UserID"`
 }
 
 type Page struct {
-	ID          uint      `gorm:"primaryKey"`
-	UserID      uint      `gorm:"index;not null"`
-	SegmentID   uint      `gorm:"index"`
-	UserInput   string    `gorm:"type:text"`
-	AgentOutput string    `gorm:"type:text"`
-	Status      string    `gorm:"type:text;not null;check:status IN ('in_stm','in_mtm','in_ltm','invalid')"`
-	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	ID             uint      `gorm:"primaryKey"`
+	UserID         uint      `gorm:"index;not null"`
+	ConversationID uint      `gorm:"index"`
+	SegmentID      uint      `gorm:"index"`
+	UserInput      string    `gorm:"type:text"`
+	AgentOutput    string    `gorm:"type:text"`
+	Truncated      bool      `gorm:"not null;default:false"`
+	Status         string    `gorm:"type:text;not null;check:status IN ('in_stm','in_mtm','in_ltm','invalid')"`
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
 }
 
 type Segment struct {
@@ -42,8 +45,23 @@ type MateMessage struct {
 	UserID uint `json:"user_id"`
 }
 
+type GetMessagesRequest struct {
+	UserID         uint
+	ConversationID uint
+	StartTime      int64
+	EndTime        int64
+	Limit          int
+}
+
 type Correlation struct {
 	Page      *Page   `json:"page"`
 	Score     float32 `json:"score"`
 	SegmentID uint    `json:"segment_id"`
 }
+
+type PurgeResult struct {
+	Pages            int64
+	Segments         int64
+	LongTermMemories int64
+	CacheKeys        int64
+}
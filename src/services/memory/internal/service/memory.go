@@ -5,10 +5,11 @@ import (
 
 	memoryapi "github.com/Fl0rencess720/Doria/src/rpc/memory"
 	"github.com/Fl0rencess720/Doria/src/services/memory/internal/biz"
+	"github.com/Fl0rencess720/Doria/src/services/memory/internal/models"
 )
 
 func (s *MemoryService) GetMemory(ctx context.Context, req *memoryapi.GetMemoryRequest) (*memoryapi.GetMemoryResponse, error) {
-	memory, err := s.memoryUseCase.RetrieveMemory(ctx, uint(req.UserId), req.Prompt)
+	memory, err := s.memoryUseCase.RetrieveMemory(ctx, uint(req.UserId), uint(req.ConversationId), req.Prompt)
 	if err != nil {
 		return nil, err
 	}
@@ -46,3 +47,86 @@ func (s *MemoryService) GetMemory(ctx context.Context, req *memoryapi.GetMemoryR
 
 	return resp, nil
 }
+
+func (s *MemoryService) GetMessages(ctx context.Context, req *memoryapi.GetMessagesRequest) (*memoryapi.GetMessagesResponse, error) {
+	messages, err := s.memoryUseCase.GetMessages(ctx, &models.GetMessagesRequest{
+		UserID:         uint(req.UserId),
+		ConversationID: uint(req.ConversationId),
+		StartTime:      req.StartTime,
+		EndTime:        req.EndTime,
+		Limit:          int(req.Limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &memoryapi.GetMessagesResponse{
+		Messages: make([]*memoryapi.Message, len(messages)),
+	}
+	for i, message := range messages {
+		resp.Messages[i] = &memoryapi.Message{
+			Role:       message.Role,
+			Content:    message.Content,
+			CreateTime: message.CreateTime,
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *MemoryService) GetMemoryTiers(ctx context.Context, req *memoryapi.GetMemoryTiersRequest) (*memoryapi.GetMemoryTiersResponse, error) {
+	tiers, err := s.memoryUseCase.GetMemoryTiers(ctx, uint(req.UserId))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &memoryapi.GetMemoryTiersResponse{
+		ShortTerm: make([]*memoryapi.MemoryPage, len(tiers.ShortTerm)),
+		MidTerm:   make([]*memoryapi.MemorySegment, len(tiers.MidTerm)),
+		LongTerm:  make([]*memoryapi.MemoryKnowledge, len(tiers.LongTerm)),
+	}
+	for i, page := range tiers.ShortTerm {
+		resp.ShortTerm[i] = &memoryapi.MemoryPage{
+			Id:             uint32(page.ID),
+			ConversationId: uint32(page.ConversationID),
+			UserInput:      page.UserInput,
+			AgentOutput:    page.AgentOutput,
+			CreateTime:     page.CreatedAt.Unix(),
+		}
+	}
+	for i, segment := range tiers.MidTerm {
+		resp.MidTerm[i] = &memoryapi.MemorySegment{
+			Id:        uint32(segment.ID),
+			Overview:  segment.Overview,
+			Visit:     int32(segment.Visit),
+			LastVisit: segment.LastVisit.Unix(),
+			PageCount: int32(len(segment.Pages)),
+		}
+	}
+	for i, ltm := range tiers.LongTerm {
+		resp.LongTerm[i] = &memoryapi.MemoryKnowledge{
+			Id:      uint32(ltm.ID),
+			Content: ltm.Content,
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *MemoryService) ReprocessMemory(ctx context.Context, req *memoryapi.ReprocessMemoryRequest) (*memoryapi.ReprocessMemoryResponse, error) {
+	s.memoryUseCase.ReprocessMemory(uint(req.UserId))
+	return &memoryapi.ReprocessMemoryResponse{}, nil
+}
+
+func (s *MemoryService) PurgeUserMemory(ctx context.Context, req *memoryapi.PurgeUserMemoryRequest) (*memoryapi.PurgeUserMemoryResponse, error) {
+	result, err := s.memoryUseCase.PurgeUserMemory(ctx, uint(req.UserId))
+	if err != nil {
+		return nil, err
+	}
+	return &memoryapi.PurgeUserMemoryResponse{
+		Pages:            result.Pages,
+		Segments:         result.Segments,
+		LongTermMemories: result.LongTermMemories,
+		CacheKeys:        result.CacheKeys,
+	}, nil
+}
@@ -9,7 +9,10 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/Fl0rencess720/Doria/src/common/health"
+	"github.com/Fl0rencess720/Doria/src/common/metrics"
 	"github.com/Fl0rencess720/Doria/src/common/registry"
+	"github.com/Fl0rencess720/Doria/src/common/rpcerr"
 	memoryapi "github.com/Fl0rencess720/Doria/src/rpc/memory"
 	"github.com/Fl0rencess720/Doria/src/services/memory/internal/biz"
 	"github.com/google/wire"
@@ -28,11 +31,12 @@ type MemoryService struct {
 	registry    *registry.RegistrationManager
 	server      *grpc.Server
 	listener    net.Listener
+	health      *health.Checker
 
 	memoryUseCase *biz.MemoryUseCase
 }
 
-func NewMemoryService(serviceName string, memoryUseCase *biz.MemoryUseCase) *MemoryService {
+func NewMemoryService(serviceName string, memoryUseCase *biz.MemoryUseCase, checker *health.Checker) *MemoryService {
 	ctx := context.Background()
 
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", viper.GetInt("server.grpc.port")))
@@ -53,6 +57,8 @@ func NewMemoryService(serviceName string, memoryUseCase *biz.MemoryUseCase) *Mem
 	server := grpc.NewServer(
 		grpc.KeepaliveEnforcementPolicy(kaep),
 		grpc.KeepaliveParams(kasp),
+		grpc.ChainUnaryInterceptor(metrics.UnaryServerInterceptor(), rpcerr.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(metrics.StreamServerInterceptor(), rpcerr.StreamServerInterceptor()),
 	)
 
 	registrationManager := registry.NewRegistrationManager()
@@ -62,10 +68,12 @@ func NewMemoryService(serviceName string, memoryUseCase *biz.MemoryUseCase) *Mem
 		registry:      registrationManager,
 		server:        server,
 		listener:      lis,
+		health:        checker,
 		memoryUseCase: memoryUseCase,
 	}
 
 	memoryapi.RegisterMemoryServiceServer(server, s)
+	checker.Register(server)
 
 	memoryUseCase.Start(ctx)
 
@@ -80,7 +88,8 @@ func (s *MemoryService) Start() error {
 	}
 	s.serviceID = serviceID
 
-	go s.registry.SetTTLHealthCheck()
+	s.health.Start(context.Background())
+	go s.registry.SetTTLHealthCheck(s.health.Err)
 
 	go func() {
 		if err := s.server.Serve(s.listener); err != nil {
@@ -98,6 +107,7 @@ func (s *MemoryService) Stop() error {
 				zap.Error(err))
 		}
 	}
+	s.health.Shutdown()
 	zap.L().Info("Shutting down gRPC server...")
 	s.server.GracefulStop()
 	return nil
@@ -7,6 +7,7 @@
 package main
 
 import (
+	"github.com/Fl0rencess720/Doria/src/common/health"
 	"github.com/Fl0rencess720/Doria/src/services/tts/configs"
 	"github.com/Fl0rencess720/Doria/src/services/tts/internal/biz"
 	"github.com/Fl0rencess720/Doria/src/services/tts/internal/data"
@@ -19,7 +20,8 @@ func wireApp() *App {
 	string2 := configs.GetServiceName()
 	ttsRepo := data.NewTTSRepo()
 	ttsUseCase := biz.NewTTSUseCase(ttsRepo)
-	ttsService := service.NewTTSService(string2, ttsUseCase)
+	checker := health.NewChecker()
+	ttsService := service.NewTTSService(string2, ttsUseCase, checker)
 	app := NewApp(ttsService)
 	return app
 }
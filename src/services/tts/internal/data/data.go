@@ -1,5 +1,10 @@
 package data
 
-import "github.com/google/wire"
+import (
+	"github.com/Fl0rencess720/Doria/src/common/health"
+	"github.com/google/wire"
+)
 
-var ProviderSet = wire.NewSet(NewTTSRepo)
+// The tts service owns no stateful dependencies, so its checker has no checks
+// and only reflects whether the process is serving.
+var ProviderSet = wire.NewSet(NewTTSRepo, health.NewChecker)
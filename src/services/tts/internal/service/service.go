@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
@@ -8,7 +9,10 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/Fl0rencess720/Doria/src/common/health"
+	"github.com/Fl0rencess720/Doria/src/common/metrics"
 	"github.com/Fl0rencess720/Doria/src/common/registry"
+	"github.com/Fl0rencess720/Doria/src/common/rpcerr"
 	ttsapi "github.com/Fl0rencess720/Doria/src/rpc/tts"
 	"github.com/Fl0rencess720/Doria/src/services/tts/internal/biz"
 	"github.com/google/wire"
@@ -28,11 +32,12 @@ type TTSService struct {
 	registry    *registry.RegistrationManager
 	server      *grpc.Server
 	listener    net.Listener
+	health      *health.Checker
 
 	ttsUseCase *biz.TTSUseCase
 }
 
-func NewTTSService(serviceName string, ttsUseCase *biz.TTSUseCase) *TTSService {
+func NewTTSService(serviceName string, ttsUseCase *biz.TTSUseCase, checker *health.Checker) *TTSService {
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", viper.GetInt("server.grpc.port")))
 	if err != nil {
 		panic(err)
@@ -51,6 +56,8 @@ func NewTTSService(serviceName string, ttsUseCase *biz.TTSUseCase) *TTSService {
 	server := grpc.NewServer(
 		grpc.KeepaliveEnforcementPolicy(kaep),
 		grpc.KeepaliveParams(kasp),
+		grpc.ChainUnaryInterceptor(metrics.UnaryServerInterceptor(), rpcerr.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(metrics.StreamServerInterceptor(), rpcerr.StreamServerInterceptor()),
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 	)
 
@@ -61,10 +68,12 @@ func NewTTSService(serviceName string, ttsUseCase *biz.TTSUseCase) *TTSService {
 		registry:    registrationManager,
 		server:      server,
 		listener:    lis,
+		health:      checker,
 		ttsUseCase:  ttsUseCase,
 	}
 
 	ttsapi.RegisterTTSServiceServer(server, s)
+	checker.Register(server)
 
 	return s
 }
@@ -76,7 +85,8 @@ func (s *TTSService) Start() error {
 	}
 	s.serviceID = serviceID
 
-	go s.registry.SetTTLHealthCheck()
+	s.health.Start(context.Background())
+	go s.registry.SetTTLHealthCheck(s.health.Err)
 
 	go func() {
 		if err := s.server.Serve(s.listener); err != nil {
@@ -94,6 +104,7 @@ func (s *TTSService) Stop() error {
 				zap.Error(err))
 		}
 	}
+	s.health.Shutdown()
 	zap.L().Info("Shutting down gRPC server...")
 	s.server.GracefulStop()
 	return nil
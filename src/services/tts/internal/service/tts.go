@@ -2,15 +2,19 @@ package service
 
 import (
 	"context"
+	"time"
 
+	"github.com/Fl0rencess720/Doria/src/common/metrics"
 	ttsapi "github.com/Fl0rencess720/Doria/src/rpc/tts"
 )
 
 func (s *TTSService) SynthesizeSpeech(ctx context.Context, req *ttsapi.SynthesizeSpeechRequest) (*ttsapi.SynthesizeSpeechResponse, error) {
+	start := time.Now()
 	audioContent, err := s.ttsUseCase.SynthesizeSpeech(req.Text)
 	if err != nil {
 		return nil, err
 	}
+	metrics.TTSSynthesisSeconds.Observe(time.Since(start).Seconds())
 
 	return &ttsapi.SynthesizeSpeechResponse{
 		AudioContent: audioContent,
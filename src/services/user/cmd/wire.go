@@ -11,6 +11,7 @@ import (
 	"github.com/Fl0rencess720/Doria/src/services/user/configs"
 	"github.com/Fl0rencess720/Doria/src/services/user/internal/biz"
 	"github.com/Fl0rencess720/Doria/src/services/user/internal/data"
+	"github.com/Fl0rencess720/Doria/src/services/user/internal/pkgs/password"
 	"github.com/Fl0rencess720/Doria/src/services/user/internal/service"
 )
 
@@ -31,5 +32,6 @@ func wireApp() *App {
 		service.ProviderSet,
 		biz.ProviderSet,
 		data.ProviderSet,
+		password.ProviderSet,
 	))
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/Fl0rencess720/Doria/src/services/user/configs"
 	"github.com/Fl0rencess720/Doria/src/services/user/internal/biz"
 	"github.com/Fl0rencess720/Doria/src/services/user/internal/data"
+	"github.com/Fl0rencess720/Doria/src/services/user/internal/pkgs/password"
 	"github.com/Fl0rencess720/Doria/src/services/user/internal/service"
 )
 
@@ -20,8 +21,17 @@ func wireApp() *App {
 	db := data.NewPostgres()
 	client := data.NewRedis()
 	userRepo := data.NewUserRepo(db, client)
-	userUseCase := biz.NewUserUseCase(userRepo)
-	userService := service.NewUserService(string2, userUseCase)
+	verificationRepo := data.NewVerificationRepo(client)
+	loginGuardRepo := data.NewLoginGuardRepo(client)
+	smsSender := data.NewSMSSender()
+	hasher := password.NewHasher()
+	userUseCase := biz.NewUserUseCase(userRepo, verificationRepo, loginGuardRepo, smsSender, hasher)
+	deletionRepo := data.NewDeletionRepo(db, client)
+	mateServiceClient := data.NewMateClient()
+	memoryServiceClient := data.NewMemoryClient()
+	deletionUseCase := biz.NewDeletionUseCase(userRepo, deletionRepo, hasher, mateServiceClient, memoryServiceClient)
+	checker := data.NewHealthChecker(db, client)
+	userService := service.NewUserService(string2, userUseCase, deletionUseCase, checker)
 	app := NewApp(userService)
 	return app
 }
@@ -0,0 +1,80 @@
+package biz
+
+import (
+	"context"
+
+	"github.com/Fl0rencess720/Doria/src/common/rbac"
+	"github.com/Fl0rencess720/Doria/src/common/rpcerr"
+	"github.com/Fl0rencess720/Doria/src/services/user/internal/models"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+var ErrInvalidRole = rpcerr.New(rpcerr.ReasonValidation, "unknown role")
+
+type UserAccess struct {
+	Role        string
+	Permissions []string
+	Disabled    bool
+}
+
+func (uc *UserUseCase) GetUserAccess(ctx context.Context, userID uint) (*UserAccess, error) {
+	user, err := uc.repo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserAccess{
+		Role:        user.Role,
+		Permissions: rbac.Permissions(user.Role),
+		Disabled:    user.Disabled,
+	}, nil
+}
+
+func (uc *UserUseCase) ListUsers(ctx context.Context, query string, page, pageSize int) ([]*models.User, int64, error) {
+	offset, limit := pagination(page, pageSize)
+	return uc.repo.ListUsers(ctx, query, offset, limit)
+}
+
+func (uc *UserUseCase) SetUserDisabled(ctx context.Context, userID uint, disabled bool) error {
+	if _, err := uc.repo.GetUser(ctx, userID); err != nil {
+		return err
+	}
+
+	return uc.repo.UpdateUser(ctx, userID, map[string]any{"disabled": disabled})
+}
+
+func (uc *UserUseCase) SetUserRole(ctx context.Context, userID uint, role string) error {
+	if !rbac.ValidRole(role) {
+		return ErrInvalidRole
+	}
+	if _, err := uc.repo.GetUser(ctx, userID); err != nil {
+		return err
+	}
+
+	return uc.repo.UpdateUser(ctx, userID, map[string]any{"role": role})
+}
+
+func (uc *UserUseCase) RecordAudit(ctx context.Context, log *models.AuditLog) error {
+	return uc.repo.CreateAuditLog(ctx, log)
+}
+
+func (uc *UserUseCase) ListAuditLogs(ctx context.Context, actorID uint, page, pageSize int) ([]*models.AuditLog, int64, error) {
+	offset, limit := pagination(page, pageSize)
+	return uc.repo.ListAuditLogs(ctx, actorID, offset, limit)
+}
+
+func pagination(page, pageSize int) (offset, limit int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	pageSize = min(pageSize, maxPageSize)
+
+	return (page - 1) * pageSize, pageSize
+}
@@ -2,4 +2,4 @@ package biz
 
 import "github.com/google/wire"
 
-var ProviderSet = wire.NewSet(NewUserUseCase)
\ No newline at end of file
+var ProviderSet = wire.NewSet(NewUserUseCase, NewDeletionUseCase)
\ No newline at end of file
@@ -0,0 +1,357 @@
+package biz
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/Fl0rencess720/Doria/src/common/metrics"
+	"github.com/Fl0rencess720/Doria/src/common/rpcerr"
+	mateapi "github.com/Fl0rencess720/Doria/src/rpc/mate"
+	memoryapi "github.com/Fl0rencess720/Doria/src/rpc/memory"
+	"github.com/Fl0rencess720/Doria/src/services/user/internal/models"
+	"github.com/Fl0rencess720/Doria/src/services/user/internal/pkgs/password"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+const (
+	DeletionPending   = "pending"
+	DeletionCompleted = "completed"
+
+	DeletionStepMemory  = "memory"
+	DeletionStepMate    = "mate"
+	DeletionStepGateway = "gateway"
+	DeletionStepUser    = "user"
+)
+
+// deletionSteps is the order a deletion runs in. Pages reference both
+// conversations and users, so memory goes first; the user row goes last so an
+// unfinished deletion can always tell whose data it is still erasing.
+var deletionSteps = []string{DeletionStepMemory, DeletionStepMate, DeletionStepGateway, DeletionStepUser}
+
+var (
+	ErrDeletionNotFound   = rpcerr.New(rpcerr.ReasonNotFound, "account deletion not found")
+	ErrDeletionInProgress = rpcerr.New(rpcerr.ReasonValidation, "account deletion already requested")
+)
+
+type DeletionRepo interface {
+	// CreateDeletion disables the user and records the deletion with its
+	// steps in one transaction.
+	CreateDeletion(ctx context.Context, deletion *models.AccountDeletion) error
+	GetDeletion(ctx context.Context, deletionID string) (*models.AccountDeletion, error)
+	// ListDueDeletions returns pending deletions whose next attempt is due.
+	ListDueDeletions(ctx context.Context, now time.Time, limit int) ([]*models.AccountDeletion, error)
+	LockDeletion(ctx context.Context, deletionID string, ttl time.Duration) (bool, error)
+	UnlockDeletion(ctx context.Context, deletionID string) error
+	CompleteDeletionStep(ctx context.Context, deletionID, step, detail string, at time.Time) error
+	RecordDeletionFailure(ctx context.Context, deletionID, lastError string, nextAttemptAt time.Time) error
+	CompleteDeletion(ctx context.Context, deletionID string, at time.Time) error
+	// PurgeGatewayData erases the sessions, stored idempotent responses,
+	// chat stream buffers and quota plan the gateway keeps for userID.
+	PurgeGatewayData(ctx context.Context, userID uint) (map[string]int64, error)
+	// PurgeUser erases what the user service itself keeps about userID and
+	// returns how much of each kind it removed.
+	PurgeUser(ctx context.Context, userID uint) (map[string]int64, error)
+}
+
+// DeletionPolicy controls the deletion worker. A new deletion first runs after
+// Delay, which must outlast the gateway's chat generation timeout plus the
+// memory consumer's lag: generations detached from their request and queued
+// memory signals can still write pages for the user until then. Every
+// PollInterval the worker picks up to BatchSize due deletions; a failed one
+// is retried after RetryBase, doubled per attempt up to RetryMax.
+type DeletionPolicy struct {
+	Delay        time.Duration
+	PollInterval time.Duration
+	BatchSize    int
+	StepTimeout  time.Duration
+	RetryBase    time.Duration
+	RetryMax     time.Duration
+}
+
+func loadDeletionPolicy() *DeletionPolicy {
+	policy := &DeletionPolicy{
+		Delay:        15 * time.Minute,
+		PollInterval: 10 * time.Second,
+		BatchSize:    10,
+		StepTimeout:  time.Minute,
+		RetryBase:    30 * time.Second,
+		RetryMax:     time.Hour,
+	}
+	if v := viper.GetDuration("deletion.delay"); v > 0 {
+		policy.Delay = v
+	}
+	if v := viper.GetDuration("deletion.poll_interval"); v > 0 {
+		policy.PollInterval = v
+	}
+	if v := viper.GetInt("deletion.batch_size"); v > 0 {
+		policy.BatchSize = v
+	}
+	if v := viper.GetDuration("deletion.step_timeout"); v > 0 {
+		policy.StepTimeout = v
+	}
+	if v := viper.GetDuration("deletion.retry_base"); v > 0 {
+		policy.RetryBase = v
+	}
+	if v := viper.GetDuration("deletion.retry_max"); v > 0 {
+		policy.RetryMax = v
+	}
+	return policy
+}
+
+func (p *DeletionPolicy) retryDelay(attempts int) time.Duration {
+	delay := p.RetryBase
+	for i := 0; i < attempts && delay < p.RetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, p.RetryMax)
+}
+
+// lockTTL outlives a run that spends the full timeout on every step.
+func (p *DeletionPolicy) lockTTL() time.Duration {
+	return p.StepTimeout * time.Duration(len(deletionSteps)+1)
+}
+
+type DeletionUseCase struct {
+	repo         UserRepo
+	deletionRepo DeletionRepo
+	hasher       *password.Hasher
+	mateClient   mateapi.MateServiceClient
+	memoryClient memoryapi.MemoryServiceClient
+	policy       *DeletionPolicy
+}
+
+func NewDeletionUseCase(repo UserRepo, deletionRepo DeletionRepo, hasher *password.Hasher, mateClient mateapi.MateServiceClient, memoryClient memoryapi.MemoryServiceClient) *DeletionUseCase {
+	return &DeletionUseCase{
+		repo:         repo,
+		deletionRepo: deletionRepo,
+		hasher:       hasher,
+		mateClient:   mateClient,
+		memoryClient: memoryClient,
+		policy:       loadDeletionPolicy(),
+	}
+}
+
+// DeleteAccount disables the account at once and queues the purge. The
+// returned token is shown only here; it authorises reading the deletion
+// status after the account, and with it every session, is gone.
+func (uc *DeletionUseCase) DeleteAccount(ctx context.Context, userID uint, plain string) (string, string, error) {
+	user, err := uc.repo.GetUser(ctx, userID)
+	if err != nil {
+		return "", "", err
+	}
+	// Visitors never had a password; their token is all the proof there is.
+	if user.Status != StatusVisitor {
+		ok, _, err := uc.hasher.Verify(plain, user.Password)
+		if err != nil {
+			return "", "", err
+		}
+		if !ok {
+			return "", "", ErrWrongPassword
+		}
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	token := hex.EncodeToString(raw)
+
+	deletion := &models.AccountDeletion{
+		ID:            uuid.NewString(),
+		UserID:        user.ID,
+		Status:        DeletionPending,
+		TokenHash:     hashDeletionToken(token),
+		NextAttemptAt: time.Now().Add(uc.policy.Delay),
+	}
+	for i, step := range deletionSteps {
+		deletion.Steps = append(deletion.Steps, &models.AccountDeletionStep{
+			Name:     step,
+			Position: i,
+			Status:   DeletionPending,
+		})
+	}
+	if err := uc.deletionRepo.CreateDeletion(ctx, deletion); err != nil {
+		return "", "", err
+	}
+
+	zap.L().Info("account deletion requested",
+		zap.String("deletionID", deletion.ID),
+		zap.Uint("userID", user.ID))
+	return deletion.ID, token, nil
+}
+
+func (uc *DeletionUseCase) GetAccountDeletion(ctx context.Context, deletionID, token string) (*models.AccountDeletion, error) {
+	if err := uuid.Validate(deletionID); err != nil {
+		return nil, ErrDeletionNotFound
+	}
+
+	deletion, err := uc.deletionRepo.GetDeletion(ctx, deletionID)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hashDeletionToken(token)), []byte(deletion.TokenHash)) != 1 {
+		return nil, ErrDeletionNotFound
+	}
+	return deletion, nil
+}
+
+// Start runs the deletion worker until ctx is done. Every replica runs one;
+// the per-deletion lock keeps them from working on the same account.
+func (uc *DeletionUseCase) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(uc.policy.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				uc.runDueDeletions(ctx)
+			}
+		}
+	}()
+}
+
+func (uc *DeletionUseCase) runDueDeletions(ctx context.Context) {
+	deletions, err := uc.deletionRepo.ListDueDeletions(ctx, time.Now(), uc.policy.BatchSize)
+	if err != nil {
+		zap.L().Error("list due account deletions error", zap.Error(err))
+		return
+	}
+
+	for _, deletion := range deletions {
+		if ctx.Err() != nil {
+			return
+		}
+		uc.runDeletion(ctx, deletion.ID)
+	}
+}
+
+func (uc *DeletionUseCase) runDeletion(ctx context.Context, deletionID string) {
+	locked, err := uc.deletionRepo.LockDeletion(ctx, deletionID, uc.policy.lockTTL())
+	if err != nil {
+		zap.L().Error("lock account deletion error", zap.String("deletionID", deletionID), zap.Error(err))
+		return
+	}
+	if !locked {
+		return
+	}
+	defer func() {
+		if err := uc.deletionRepo.UnlockDeletion(context.WithoutCancel(ctx), deletionID); err != nil {
+			zap.L().Error("unlock account deletion error", zap.String("deletionID", deletionID), zap.Error(err))
+		}
+	}()
+
+	// Reload under the lock: another replica may have finished steps since
+	// the deletion was listed.
+	deletion, err := uc.deletionRepo.GetDeletion(ctx, deletionID)
+	if err != nil {
+		zap.L().Error("get account deletion error", zap.String("deletionID", deletionID), zap.Error(err))
+		return
+	}
+	if deletion.Status == DeletionCompleted {
+		return
+	}
+
+	completed := make(map[string]bool, len(deletion.Steps))
+	for _, step := range deletion.Steps {
+		completed[step.Name] = step.Status == DeletionCompleted
+	}
+
+	for _, step := range deletionSteps {
+		if completed[step] {
+			continue
+		}
+
+		detail, err := uc.runDeletionStep(ctx, step, deletion.UserID)
+		if err != nil {
+			metrics.AccountDeletionSteps.WithLabelValues(step, "error").Inc()
+			uc.recordDeletionFailure(ctx, deletion, step, err)
+			return
+		}
+		metrics.AccountDeletionSteps.WithLabelValues(step, "success").Inc()
+
+		body, err := json.Marshal(detail)
+		if err != nil {
+			zap.L().Error("marshal account deletion step error", zap.Error(err))
+			return
+		}
+		if err := uc.deletionRepo.CompleteDeletionStep(ctx, deletion.ID, step, string(body), time.Now()); err != nil {
+			zap.L().Error("complete account deletion step error",
+				zap.String("deletionID", deletion.ID),
+				zap.String("step", step),
+				zap.Error(err))
+			return
+		}
+	}
+
+	if err := uc.deletionRepo.CompleteDeletion(ctx, deletion.ID, time.Now()); err != nil {
+		zap.L().Error("complete account deletion error", zap.String("deletionID", deletion.ID), zap.Error(err))
+		return
+	}
+	zap.L().Info("account deletion completed",
+		zap.String("deletionID", deletion.ID),
+		zap.Uint("userID", deletion.UserID))
+}
+
+// runDeletionStep erases one service's share of the account. Every step is
+// idempotent, so a step that failed halfway is simply run again.
+func (uc *DeletionUseCase) runDeletionStep(ctx context.Context, step string, userID uint) (map[string]int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.policy.StepTimeout)
+	defer cancel()
+
+	switch step {
+	case DeletionStepMemory:
+		resp, err := uc.memoryClient.PurgeUserMemory(ctx, &memoryapi.PurgeUserMemoryRequest{UserId: int32(userID)})
+		if err != nil {
+			return nil, err
+		}
+		return map[string]int64{
+			"pages":              resp.Pages,
+			"segments":           resp.Segments,
+			"long_term_memories": resp.LongTermMemories,
+			"cache_keys":         resp.CacheKeys,
+		}, nil
+	case DeletionStepMate:
+		resp, err := uc.mateClient.PurgeUserData(ctx, &mateapi.PurgeUserDataRequest{UserId: int32(userID)})
+		if err != nil {
+			return nil, err
+		}
+		return map[string]int64{
+			"conversations": resp.Conversations,
+			"usage_keys":    resp.UsageKeys,
+		}, nil
+	case DeletionStepGateway:
+		return uc.deletionRepo.PurgeGatewayData(ctx, userID)
+	default:
+		return uc.deletionRepo.PurgeUser(ctx, userID)
+	}
+}
+
+func (uc *DeletionUseCase) recordDeletionFailure(ctx context.Context, deletion *models.AccountDeletion, step string, stepErr error) {
+	retryAfter := uc.policy.retryDelay(deletion.Attempts)
+	zap.L().Warn("account deletion step failed",
+		zap.String("deletionID", deletion.ID),
+		zap.String("step", step),
+		zap.Int("attempts", deletion.Attempts+1),
+		zap.Duration("retryAfter", retryAfter),
+		zap.Error(stepErr))
+
+	lastError := step + ": " + stepErr.Error()
+	if err := uc.deletionRepo.RecordDeletionFailure(ctx, deletion.ID, lastError, time.Now().Add(retryAfter)); err != nil {
+		zap.L().Error("record account deletion failure error", zap.String("deletionID", deletion.ID), zap.Error(err))
+	}
+}
+
+func hashDeletionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
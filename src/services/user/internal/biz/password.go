@@ -0,0 +1,127 @@
+package biz
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Fl0rencess720/Doria/src/common/rpcerr"
+)
+
+const minPasswordLength = 8
+
+var (
+	ErrWeakPassword = rpcerr.New(rpcerr.ReasonValidation, "password is too short")
+	ErrNoPassword   = rpcerr.New(rpcerr.ReasonValidation, "account has no password, upgrade it first")
+)
+
+type ResetPasswordReq struct {
+	Phone       string
+	Code        string
+	NewPassword string
+}
+
+type ChangePasswordReq struct {
+	UserID          uint
+	CurrentPassword string
+	NewPassword     string
+}
+
+// RequestPasswordReset sends a reset_password code. Unknown phone numbers get
+// the same answer without a message being sent.
+func (uc *UserUseCase) RequestPasswordReset(ctx context.Context, phone, clientIP string) (*SendCodeResp, error) {
+	return uc.SendVerificationCode(ctx, &SendCodeReq{
+		Phone:    phone,
+		Purpose:  PurposeResetPassword,
+		ClientIP: clientIP,
+	})
+}
+
+// ResetPassword sets a new password for the owner of phone once the reset
+// code checks out. Revoking existing sessions is left to the gateway, which
+// owns them.
+func (uc *UserUseCase) ResetPassword(ctx context.Context, req *ResetPasswordReq) (uint, error) {
+	if err := checkPhone(req.Phone); err != nil {
+		return 0, err
+	}
+	if err := checkPassword(req.NewPassword); err != nil {
+		return 0, err
+	}
+
+	if err := uc.verifyCode(ctx, PurposeResetPassword, req.Phone, req.Code); err != nil {
+		return 0, err
+	}
+
+	user, err := uc.repo.GetUserByPhone(ctx, req.Phone)
+	if err != nil {
+		return 0, err
+	}
+	if user.Disabled {
+		return 0, ErrUserDisabled
+	}
+
+	if err := uc.setPassword(ctx, user.ID, req.NewPassword); err != nil {
+		return 0, err
+	}
+
+	uc.resetLoginFailures(ctx, LockScopePhone, req.Phone)
+	return user.ID, nil
+}
+
+func (uc *UserUseCase) ChangePassword(ctx context.Context, req *ChangePasswordReq) error {
+	if err := checkPassword(req.NewPassword); err != nil {
+		return err
+	}
+
+	if err := uc.checkCurrentPassword(ctx, req.UserID, req.CurrentPassword); err != nil {
+		return err
+	}
+
+	return uc.setPassword(ctx, req.UserID, req.NewPassword)
+}
+
+// checkCurrentPassword re-authenticates a signed-in user before a credential
+// change. Attempts go through the login guard keyed by user ID, so a stolen
+// session cannot be used to guess the password. Visitors have none to check.
+func (uc *UserUseCase) checkCurrentPassword(ctx context.Context, userID uint, plain string) error {
+	user, err := uc.repo.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Status == StatusVisitor || user.Password == "" {
+		return ErrNoPassword
+	}
+
+	subject := strconv.FormatUint(uint64(userID), 10)
+	if err := uc.checkLoginAllowed(ctx, LockScopeUser, subject, ""); err != nil {
+		return err
+	}
+
+	ok, _, err := uc.hasher.Verify(plain, user.Password)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		uc.recordLoginFailure(ctx, LockScopeUser, subject, "")
+		return ErrWrongPassword
+	}
+
+	uc.resetLoginFailures(ctx, LockScopeUser, subject)
+	return nil
+}
+
+// checkPassword enforces the password rules wherever a password is chosen:
+// registration, visitor upgrade, reset and change.
+func checkPassword(plain string) error {
+	if len(plain) < minPasswordLength {
+		return ErrWeakPassword
+	}
+	return nil
+}
+
+func (uc *UserUseCase) setPassword(ctx context.Context, userID uint, plain string) error {
+	passwordHash, err := uc.hasher.Hash(plain)
+	if err != nil {
+		return err
+	}
+	return uc.repo.UpdatePassword(ctx, userID, passwordHash)
+}
@@ -0,0 +1,22 @@
+package biz
+
+import (
+	"regexp"
+
+	"github.com/Fl0rencess720/Doria/src/common/rpcerr"
+)
+
+// phonePattern accepts E.164 numbers and mainland China mobile numbers
+// without a country code, the format accounts were created with so far.
+var phonePattern = regexp.MustCompile(`^(\+[1-9][0-9]{6,14}|1[3-9][0-9]{9})$`)
+
+var ErrInvalidPhone = rpcerr.New(rpcerr.ReasonValidation, "invalid phone number")
+
+// checkPhone validates a phone number before anything is sent to or stored
+// for it.
+func checkPhone(phone string) error {
+	if !phonePattern.MatchString(phone) {
+		return ErrInvalidPhone
+	}
+	return nil
+}
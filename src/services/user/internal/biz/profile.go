@@ -0,0 +1,147 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Fl0rencess720/Doria/src/common/rpcerr"
+	"github.com/Fl0rencess720/Doria/src/services/user/internal/models"
+	"golang.org/x/text/language"
+)
+
+const (
+	maxNameLength      = 32
+	maxAvatarURLLength = 512
+	defaultTTSSpeed    = 50
+	maxTTSSpeed        = 100
+)
+
+var (
+	// ErrProfileNotFound is returned by the repo for users who never saved a
+	// profile. It does not leave the service; callers get the defaults.
+	ErrProfileNotFound = errors.New("profile not found")
+
+	ErrInvalidNickname  = rpcerr.New(rpcerr.ReasonValidation, "nickname is too long")
+	ErrInvalidAddressAs = rpcerr.New(rpcerr.ReasonValidation, "address_as is too long")
+	ErrInvalidAvatarURL = rpcerr.New(rpcerr.ReasonValidation, "avatar_url must be an http or https url")
+	ErrInvalidTimezone  = rpcerr.New(rpcerr.ReasonValidation, "unknown timezone")
+	ErrInvalidLocale    = rpcerr.New(rpcerr.ReasonValidation, "invalid locale")
+	ErrInvalidTTSVoice  = rpcerr.New(rpcerr.ReasonValidation, "invalid tts voice")
+	ErrInvalidTTSSpeed  = rpcerr.New(rpcerr.ReasonValidation, "tts speed must be between 0 and 100")
+)
+
+var ttsVoicePattern = regexp.MustCompile(`^[A-Za-z0-9_\-]{1,64}$`)
+
+// UpdateProfileReq carries only the fields the caller wants to change.
+type UpdateProfileReq struct {
+	UserID    uint
+	Nickname  *string
+	AvatarURL *string
+	Timezone  *string
+	Locale    *string
+	TTSVoice  *string
+	TTSSpeed  *int
+	AddressAs *string
+}
+
+func (uc *UserUseCase) GetProfile(ctx context.Context, userID uint) (*models.Profile, error) {
+	profile, err := uc.repo.GetProfile(ctx, userID)
+	if errors.Is(err, ErrProfileNotFound) {
+		return &models.Profile{UserID: userID, TTSSpeed: defaultTTSSpeed}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+func (uc *UserUseCase) UpdateProfile(ctx context.Context, req *UpdateProfileReq) (*models.Profile, error) {
+	if _, err := uc.repo.GetUser(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+
+	profile, err := uc.GetProfile(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Nickname != nil {
+		nickname := strings.TrimSpace(*req.Nickname)
+		if utf8.RuneCountInString(nickname) > maxNameLength {
+			return nil, ErrInvalidNickname
+		}
+		profile.Nickname = nickname
+	}
+	if req.AddressAs != nil {
+		addressAs := strings.TrimSpace(*req.AddressAs)
+		if utf8.RuneCountInString(addressAs) > maxNameLength {
+			return nil, ErrInvalidAddressAs
+		}
+		profile.AddressAs = addressAs
+	}
+	if req.AvatarURL != nil {
+		if err := validateAvatarURL(*req.AvatarURL); err != nil {
+			return nil, err
+		}
+		profile.AvatarURL = *req.AvatarURL
+	}
+	if req.Timezone != nil {
+		if *req.Timezone != "" {
+			if _, err := time.LoadLocation(*req.Timezone); err != nil {
+				return nil, ErrInvalidTimezone
+			}
+		}
+		profile.Timezone = *req.Timezone
+	}
+	if req.Locale != nil {
+		locale := *req.Locale
+		if locale != "" {
+			tag, err := language.Parse(locale)
+			if err != nil {
+				return nil, ErrInvalidLocale
+			}
+			locale = tag.String()
+		}
+		profile.Locale = locale
+	}
+	if req.TTSVoice != nil {
+		if *req.TTSVoice != "" && !ttsVoicePattern.MatchString(*req.TTSVoice) {
+			return nil, ErrInvalidTTSVoice
+		}
+		profile.TTSVoice = *req.TTSVoice
+	}
+	if req.TTSSpeed != nil {
+		if *req.TTSSpeed < 0 || *req.TTSSpeed > maxTTSSpeed {
+			return nil, ErrInvalidTTSSpeed
+		}
+		profile.TTSSpeed = *req.TTSSpeed
+	}
+
+	if err := uc.repo.SaveProfile(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+func validateAvatarURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	if len(raw) > maxAvatarURLLength {
+		return ErrInvalidAvatarURL
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidAvatarURL
+	}
+
+	return nil
+}
@@ -2,22 +2,55 @@ package biz
 
 import (
 	"context"
+	"errors"
 
+	"github.com/Fl0rencess720/Doria/src/common/rbac"
+	"github.com/Fl0rencess720/Doria/src/common/rpcerr"
 	"github.com/Fl0rencess720/Doria/src/services/user/internal/models"
-	"github.com/Fl0rencess720/Doria/src/services/user/internal/pkgs/response"
-	"github.com/Fl0rencess720/Doria/src/services/user/internal/pkgs/utils"
+	"github.com/Fl0rencess720/Doria/src/services/user/internal/pkgs/password"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrUserExists    = rpcerr.New(rpcerr.ReasonUserExists, "user already exists")
+	ErrInvalidCode   = rpcerr.New(rpcerr.ReasonInvalidCode, "invalid verification code")
+	ErrUserNotFound  = rpcerr.New(rpcerr.ReasonUserNotFound, "user not found")
+	ErrWrongPassword = rpcerr.New(rpcerr.ReasonWrongPassword, "wrong password")
+	ErrUserDisabled  = rpcerr.New(rpcerr.ReasonAccountDisabled, "account is disabled")
+	// ErrInvalidCredentials is the only failure a login reports for an
+	// unknown phone number or a wrong password.
+	ErrInvalidCredentials = rpcerr.New(rpcerr.ReasonInvalidCredentials, "wrong phone number or password")
+)
+
+const (
+	StatusUser    = "user"
+	StatusVisitor = "visitor"
 )
 
 type UserUseCase struct {
-	repo UserRepo
+	repo             UserRepo
+	verificationRepo VerificationRepo
+	loginGuard       LoginGuardRepo
+	smsSender        SMSSender
+	hasher           *password.Hasher
+	codePolicy       *CodePolicy
+	loginPolicy      *LoginGuardPolicy
 }
 
 type UserRepo interface {
 	CreateUser(ctx context.Context, user *models.User) (uint, error)
 	GetUser(ctx context.Context, userID uint) (*models.User, error)
 	FindUser(ctx context.Context, phone string) (bool, error)
-	VerifyRegisterCode(ctx context.Context, phone string, code string) (bool, error)
-	VerifyUserPassword(ctx context.Context, phone string, password string) (bool, uint, error)
+	GetUserByPhone(ctx context.Context, phone string) (*models.User, error)
+	UpdatePassword(ctx context.Context, userID uint, passwordHash string) error
+
+	GetProfile(ctx context.Context, userID uint) (*models.Profile, error)
+	SaveProfile(ctx context.Context, profile *models.Profile) error
+
+	ListUsers(ctx context.Context, query string, offset, limit int) ([]*models.User, int64, error)
+	UpdateUser(ctx context.Context, userID uint, fields map[string]any) error
+	CreateAuditLog(ctx context.Context, log *models.AuditLog) error
+	ListAuditLogs(ctx context.Context, actorID uint, offset, limit int) ([]*models.AuditLog, int64, error)
 }
 
 type UserRegisterReq struct {
@@ -26,56 +59,146 @@ type UserRegisterReq struct {
 	Password string
 }
 
+type ChangePhoneReq struct {
+	UserID          uint
+	NewPhone        string
+	Code            string
+	CurrentPassword string
+}
+
 type UserLoginReq struct {
 	Phone    string
 	Password string
+	ClientIP string
 }
 
-func NewUserUseCase(repo UserRepo) *UserUseCase {
-	return &UserUseCase{repo: repo}
+func NewUserUseCase(repo UserRepo, verificationRepo VerificationRepo, loginGuard LoginGuardRepo, smsSender SMSSender, hasher *password.Hasher) *UserUseCase {
+	return &UserUseCase{
+		repo:             repo,
+		verificationRepo: verificationRepo,
+		loginGuard:       loginGuard,
+		smsSender:        smsSender,
+		hasher:           hasher,
+		codePolicy:       loadCodePolicy(),
+		loginPolicy:      loadLoginGuardPolicy(),
+	}
 }
 
-func (uc *UserUseCase) Register(ctx context.Context, req *UserRegisterReq) (uint, uint, error) {
+func (uc *UserUseCase) Register(ctx context.Context, req *UserRegisterReq) (uint, error) {
+	if err := checkPhone(req.Phone); err != nil {
+		return 0, err
+	}
+	if err := checkPassword(req.Password); err != nil {
+		return 0, err
+	}
+
 	findUser, err := uc.repo.FindUser(ctx, req.Phone)
 	if err != nil {
-		return 0, response.OtherError, err
+		return 0, err
 	}
 
 	if findUser {
-		return 0, response.UserExistError, nil
+		return 0, ErrUserExists
 	}
 
-	verify, err := uc.repo.VerifyRegisterCode(ctx, req.Phone, req.Code)
-	if err != nil {
-		return 0, response.OtherError, err
+	if err := uc.verifyCode(ctx, PurposeRegister, req.Phone, req.Code); err != nil {
+		return 0, err
 	}
 
-	if !verify {
-		return 0, response.CodeError, nil
+	passwordHash, err := uc.hasher.Hash(req.Password)
+	if err != nil {
+		return 0, err
 	}
 
 	user := &models.User{
 		Phone:    &req.Phone,
-		Password: utils.MD5(req.Password),
-		Status:   "user",
+		Password: passwordHash,
+		Status:   StatusUser,
+		Role:     rbac.RoleUser,
+	}
+
+	return uc.repo.CreateUser(ctx, user)
+}
+
+func (uc *UserUseCase) Login(ctx context.Context, req *UserLoginReq) (uint, error) {
+	if err := uc.checkLoginAllowed(ctx, LockScopePhone, req.Phone, req.ClientIP); err != nil {
+		return 0, err
 	}
 
-	userID, err := uc.repo.CreateUser(ctx, user)
+	user, err := uc.authenticate(ctx, req)
+	if errors.Is(err, ErrInvalidCredentials) {
+		uc.recordLoginFailure(ctx, LockScopePhone, req.Phone, req.ClientIP)
+		return 0, err
+	}
 	if err != nil {
-		return 0, response.OtherError, err
+		return 0, err
+	}
+
+	uc.resetLoginFailures(ctx, LockScopePhone, req.Phone)
+	if user.Disabled {
+		return 0, ErrUserDisabled
 	}
 
-	return userID, response.Success, nil
+	return user.ID, nil
 }
 
-func (uc *UserUseCase) Login(ctx context.Context, req *UserLoginReq) (uint, uint, error) {
-	verify, userID, err := uc.repo.VerifyUserPassword(ctx, req.Phone, utils.MD5(req.Password))
+func (uc *UserUseCase) authenticate(ctx context.Context, req *UserLoginReq) (*models.User, error) {
+	user, err := uc.repo.GetUserByPhone(ctx, req.Phone)
+	if errors.Is(err, ErrUserNotFound) {
+		uc.hasher.VerifyDummy(req.Password)
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ok, needsRehash, err := uc.hasher.Verify(req.Password, user.Password)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+
+	if needsRehash {
+		uc.rehashPassword(ctx, user.ID, req.Password)
+	}
+	return user, nil
+}
+
+// rehashPassword upgrades a legacy or outdated hash with the current
+// parameters. Failing to do so must not fail the login, the next one retries.
+func (uc *UserUseCase) rehashPassword(ctx context.Context, userID uint, plain string) {
+	if err := uc.setPassword(ctx, userID, plain); err != nil {
+		zap.L().Warn("rehash password failed", zap.Uint("userID", userID), zap.Error(err))
+		return
+	}
+	zap.L().Info("password hash upgraded", zap.Uint("userID", userID))
+}
+
+// ChangePhone moves the account to a new phone number. Like a password
+// change it needs the current password, and the gateway revokes the other
+// sessions afterwards.
+func (uc *UserUseCase) ChangePhone(ctx context.Context, req *ChangePhoneReq) error {
+	if err := checkPhone(req.NewPhone); err != nil {
+		return err
+	}
+
+	if err := uc.checkCurrentPassword(ctx, req.UserID, req.CurrentPassword); err != nil {
+		return err
+	}
+
+	exists, err := uc.repo.FindUser(ctx, req.NewPhone)
 	if err != nil {
-		return 0, response.UserNotExistError, err
+		return err
 	}
-	if !verify {
-		return 0, response.PasswordError, nil
+	if exists {
+		return ErrUserExists
+	}
+
+	if err := uc.verifyCode(ctx, PurposeChangePhone, req.NewPhone, req.Code); err != nil {
+		return err
 	}
 
-	return userID, response.Success, nil
+	return uc.repo.UpdateUser(ctx, req.UserID, map[string]any{"phone": req.NewPhone})
 }
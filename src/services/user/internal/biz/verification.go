@@ -0,0 +1,169 @@
+package biz
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/Fl0rencess720/Doria/src/common/rpcerr"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+const (
+	PurposeRegister      = "register"
+	PurposeResetPassword = "reset_password"
+	PurposeChangePhone   = "change_phone"
+)
+
+var (
+	ErrInvalidPurpose  = rpcerr.New(rpcerr.ReasonValidation, "unknown verification code purpose")
+	ErrCodeTooFrequent = rpcerr.New(rpcerr.ReasonRateLimited, "verification code requested too often")
+)
+
+// SMSSender delivers text messages to a phone number. Implementations live in
+// data; the stubs there only log or append to a file for local development.
+type SMSSender interface {
+	Send(ctx context.Context, phone, content string) error
+}
+
+type VerificationRepo interface {
+	// ThrottleCodeSend counts one send for phone and ip and returns how long
+	// the caller has to wait instead when a limit in policy is reached.
+	ThrottleCodeSend(ctx context.Context, phone, ip string, policy *CodePolicy) (time.Duration, error)
+	SaveVerificationCode(ctx context.Context, purpose, phone, code string, ttl time.Duration) error
+	// ConsumeVerificationCode deletes the code once it matched or after
+	// maxAttempts wrong guesses.
+	ConsumeVerificationCode(ctx context.Context, purpose, phone, code string, maxAttempts int) (bool, error)
+}
+
+type CodePolicy struct {
+	Length          int
+	TTL             time.Duration
+	MaxAttempts     int
+	ResendInterval  time.Duration
+	PhoneDailyLimit int
+	IPHourlyLimit   int
+}
+
+type SendCodeReq struct {
+	Phone    string
+	Purpose  string
+	ClientIP string
+}
+
+type SendCodeResp struct {
+	ExpiresIn   time.Duration
+	ResendAfter time.Duration
+}
+
+func loadCodePolicy() *CodePolicy {
+	policy := &CodePolicy{
+		Length:          6,
+		TTL:             5 * time.Minute,
+		MaxAttempts:     5,
+		ResendInterval:  time.Minute,
+		PhoneDailyLimit: 10,
+		IPHourlyLimit:   30,
+	}
+	if v := viper.GetInt("verification.code_length"); v > 0 {
+		policy.Length = v
+	}
+	if v := viper.GetDuration("verification.ttl"); v > 0 {
+		policy.TTL = v
+	}
+	if v := viper.GetInt("verification.max_attempts"); v > 0 {
+		policy.MaxAttempts = v
+	}
+	if v := viper.GetDuration("verification.resend_interval"); v > 0 {
+		policy.ResendInterval = v
+	}
+	if v := viper.GetInt("verification.phone_daily_limit"); v > 0 {
+		policy.PhoneDailyLimit = v
+	}
+	if v := viper.GetInt("verification.ip_hourly_limit"); v > 0 {
+		policy.IPHourlyLimit = v
+	}
+	return policy
+}
+
+func (uc *UserUseCase) SendVerificationCode(ctx context.Context, req *SendCodeReq) (*SendCodeResp, error) {
+	if err := checkPhone(req.Phone); err != nil {
+		return nil, err
+	}
+
+	resp := &SendCodeResp{
+		ExpiresIn:   uc.codePolicy.TTL,
+		ResendAfter: uc.codePolicy.ResendInterval,
+	}
+
+	exists, err := uc.repo.FindUser(ctx, req.Phone)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.Purpose {
+	case PurposeRegister, PurposeChangePhone:
+		if exists {
+			return nil, ErrUserExists
+		}
+	case PurposeResetPassword:
+		// Answer as if a code was sent so the endpoint does not reveal
+		// which phone numbers have accounts.
+		if !exists {
+			return resp, nil
+		}
+	default:
+		return nil, ErrInvalidPurpose
+	}
+
+	wait, err := uc.verificationRepo.ThrottleCodeSend(ctx, req.Phone, req.ClientIP, uc.codePolicy)
+	if err != nil {
+		return nil, err
+	}
+	if wait > 0 {
+		return nil, ErrCodeTooFrequent.WithRetryAfter(wait)
+	}
+
+	code, err := generateCode(uc.codePolicy.Length)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.verificationRepo.SaveVerificationCode(ctx, req.Purpose, req.Phone, code, uc.codePolicy.TTL); err != nil {
+		return nil, err
+	}
+
+	content := fmt.Sprintf("Your Doria verification code is %s. It expires in %d minutes.", code, int(uc.codePolicy.TTL.Minutes()))
+	if err := uc.smsSender.Send(ctx, req.Phone, content); err != nil {
+		zap.L().Error("send verification code failed", zap.String("purpose", req.Purpose), zap.Error(err))
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (uc *UserUseCase) verifyCode(ctx context.Context, purpose, phone, code string) error {
+	ok, err := uc.verificationRepo.ConsumeVerificationCode(ctx, purpose, phone, code, uc.codePolicy.MaxAttempts)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidCode
+	}
+	return nil
+}
+
+func generateCode(length int) (string, error) {
+	var b strings.Builder
+	for range length {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(byte('0' + n.Int64()))
+	}
+	return b.String(), nil
+}
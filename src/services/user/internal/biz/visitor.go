@@ -0,0 +1,78 @@
+package biz
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/Fl0rencess720/Doria/src/common/rbac"
+	"github.com/Fl0rencess720/Doria/src/common/rpcerr"
+	"github.com/Fl0rencess720/Doria/src/services/user/internal/models"
+)
+
+var ErrNotVisitor = rpcerr.New(rpcerr.ReasonValidation, "account is not a guest account")
+
+type UpgradeVisitorReq struct {
+	UserID   uint
+	Phone    string
+	Code     string
+	Password string
+}
+
+// CreateVisitor creates an anonymous account. Visitors have no phone and no
+// usable password, so the tokens issued for them are the only way in.
+func (uc *UserUseCase) CreateVisitor(ctx context.Context) (uint, error) {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return 0, err
+	}
+
+	return uc.repo.CreateUser(ctx, &models.User{
+		Username: "visitor-" + hex.EncodeToString(suffix),
+		Status:   StatusVisitor,
+		Role:     rbac.RoleUser,
+	})
+}
+
+// UpgradeVisitor turns a visitor into a full user once the phone number is
+// verified with a register code. The user ID stays the same, so pages and
+// memory carry over.
+func (uc *UserUseCase) UpgradeVisitor(ctx context.Context, req *UpgradeVisitorReq) error {
+	if err := checkPhone(req.Phone); err != nil {
+		return err
+	}
+	if err := checkPassword(req.Password); err != nil {
+		return err
+	}
+
+	user, err := uc.repo.GetUser(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+	if user.Status != StatusVisitor {
+		return ErrNotVisitor
+	}
+
+	exists, err := uc.repo.FindUser(ctx, req.Phone)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrUserExists
+	}
+
+	if err := uc.verifyCode(ctx, PurposeRegister, req.Phone, req.Code); err != nil {
+		return err
+	}
+
+	passwordHash, err := uc.hasher.Hash(req.Password)
+	if err != nil {
+		return err
+	}
+
+	return uc.repo.UpdateUser(ctx, req.UserID, map[string]any{
+		"phone":    req.Phone,
+		"password": passwordHash,
+		"status":   StatusUser,
+	})
+}
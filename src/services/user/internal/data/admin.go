@@ -0,0 +1,54 @@
+package data
+
+import (
+	"context"
+
+	"github.com/Fl0rencess720/Doria/src/services/user/internal/models"
+)
+
+func (u *userRepo) ListUsers(ctx context.Context, query string, offset, limit int) ([]*models.User, int64, error) {
+	db := u.pg.WithContext(ctx).Model(&models.User{})
+	if query != "" {
+		like := "%" + query + "%"
+		db = db.Where("username ILIKE ? OR phone LIKE ?", like, like)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	users := []*models.User{}
+	if err := db.Order("id").Offset(offset).Limit(limit).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+func (u *userRepo) UpdateUser(ctx context.Context, userID uint, fields map[string]any) error {
+	return u.pg.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).Updates(fields).Error
+}
+
+func (u *userRepo) CreateAuditLog(ctx context.Context, log *models.AuditLog) error {
+	return u.pg.WithContext(ctx).Create(log).Error
+}
+
+func (u *userRepo) ListAuditLogs(ctx context.Context, actorID uint, offset, limit int) ([]*models.AuditLog, int64, error) {
+	db := u.pg.WithContext(ctx).Model(&models.AuditLog{})
+	if actorID != 0 {
+		db = db.Where("actor_id = ?", actorID)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	logs := []*models.AuditLog{}
+	if err := db.Order("id DESC").Offset(offset).Limit(limit).Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return logs, total, nil
+}
@@ -0,0 +1,221 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Fl0rencess720/Doria/src/consts"
+	"github.com/Fl0rencess720/Doria/src/services/user/internal/biz"
+	"github.com/Fl0rencess720/Doria/src/services/user/internal/models"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const (
+	deletionLockPrefix = "lock:account_deletion:"
+
+	// redactedTarget replaces a deleted phone number in lockout audit logs,
+	// keeping the event for the record without the personal data.
+	redactedTarget = biz.LockScopePhone + ":deleted"
+)
+
+type deletionRepo struct {
+	pg          *gorm.DB
+	redisClient *redis.Client
+}
+
+func NewDeletionRepo(pg *gorm.DB, rdb *redis.Client) biz.DeletionRepo {
+	return &deletionRepo{
+		pg:          pg,
+		redisClient: rdb,
+	}
+}
+
+func (r *deletionRepo) CreateDeletion(ctx context.Context, deletion *models.AccountDeletion) error {
+	return r.pg.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing int64
+		if err := tx.Model(&models.AccountDeletion{}).Where("user_id = ?", deletion.UserID).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return biz.ErrDeletionInProgress
+		}
+
+		if err := tx.Model(&models.User{}).Where("id = ?", deletion.UserID).Update("disabled", true).Error; err != nil {
+			return err
+		}
+		return tx.Create(deletion).Error
+	})
+}
+
+func (r *deletionRepo) GetDeletion(ctx context.Context, deletionID string) (*models.AccountDeletion, error) {
+	deletion := &models.AccountDeletion{}
+
+	err := r.pg.WithContext(ctx).
+		Preload("Steps", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Where("id = ?", deletionID).First(deletion).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, biz.ErrDeletionNotFound
+		}
+		return nil, err
+	}
+
+	return deletion, nil
+}
+
+func (r *deletionRepo) ListDueDeletions(ctx context.Context, now time.Time, limit int) ([]*models.AccountDeletion, error) {
+	var deletions []*models.AccountDeletion
+
+	err := r.pg.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", biz.DeletionPending, now).
+		Order("next_attempt_at").Limit(limit).Find(&deletions).Error
+	return deletions, err
+}
+
+func (r *deletionRepo) LockDeletion(ctx context.Context, deletionID string, ttl time.Duration) (bool, error) {
+	return r.redisClient.SetNX(ctx, deletionLockPrefix+deletionID, 1, ttl).Result()
+}
+
+func (r *deletionRepo) UnlockDeletion(ctx context.Context, deletionID string) error {
+	return r.redisClient.Del(ctx, deletionLockPrefix+deletionID).Err()
+}
+
+func (r *deletionRepo) CompleteDeletionStep(ctx context.Context, deletionID, step, detail string, at time.Time) error {
+	return r.pg.WithContext(ctx).Model(&models.AccountDeletionStep{}).
+		Where("deletion_id = ? AND name = ?", deletionID, step).
+		Updates(map[string]any{
+			"status":       biz.DeletionCompleted,
+			"detail":       detail,
+			"completed_at": at,
+		}).Error
+}
+
+func (r *deletionRepo) RecordDeletionFailure(ctx context.Context, deletionID, lastError string, nextAttemptAt time.Time) error {
+	return r.pg.WithContext(ctx).Model(&models.AccountDeletion{}).
+		Where("id = ?", deletionID).
+		Updates(map[string]any{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"last_error":      lastError,
+			"next_attempt_at": nextAttemptAt,
+		}).Error
+}
+
+func (r *deletionRepo) CompleteDeletion(ctx context.Context, deletionID string, at time.Time) error {
+	return r.pg.WithContext(ctx).Model(&models.AccountDeletion{}).
+		Where("id = ?", deletionID).
+		Updates(map[string]any{
+			"status":       biz.DeletionCompleted,
+			"last_error":   "",
+			"completed_at": at,
+		}).Error
+}
+
+// PurgeGatewayData relies on the gateway sharing this Redis. Sessions are
+// revoked when the deletion is requested, but an idempotent DELETE response
+// or a chat stream buffer written afterwards is only caught here.
+func (r *deletionRepo) PurgeGatewayData(ctx context.Context, userID uint) (map[string]int64, error) {
+	userSessionsKey := fmt.Sprintf("%s:%d", consts.UserSessionsPrefix, userID)
+	sessionIDs, err := r.redisClient.SMembers(ctx, userSessionsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	sessionKeys := []string{userSessionsKey, fmt.Sprintf("%s:%d", consts.QuotaPlanPrefix, userID)}
+	for _, sessionID := range sessionIDs {
+		sessionKeys = append(sessionKeys, fmt.Sprintf("%s:%s", consts.SessionPrefix, sessionID))
+	}
+	sessions, err := r.redisClient.Del(ctx, sessionKeys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	idempotencyKeys, err := r.deleteMatching(ctx, fmt.Sprintf("%s:user:%d:*", consts.IdempotencyPrefix, userID))
+	if err != nil {
+		return nil, err
+	}
+	chatStreams, err := r.deleteMatching(ctx, fmt.Sprintf("%s:%d:*", consts.ChatStreamBufferPrefix, userID))
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]int64{
+		"session_keys":     sessions,
+		"idempotency_keys": idempotencyKeys,
+		"chat_streams":     chatStreams,
+	}, nil
+}
+
+func (r *deletionRepo) deleteMatching(ctx context.Context, pattern string) (int64, error) {
+	var deleted int64
+
+	iter := r.redisClient.Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		n, err := r.redisClient.Del(ctx, iter.Val()).Result()
+		if err != nil {
+			return 0, err
+		}
+		deleted += n
+	}
+	return deleted, iter.Err()
+}
+
+// PurgeUser clears the phone-keyed state before the rows, since the phone is
+// only known while the user row still exists.
+func (r *deletionRepo) PurgeUser(ctx context.Context, userID uint) (map[string]int64, error) {
+	detail := map[string]int64{}
+
+	user := &models.User{}
+	err := r.pg.WithContext(ctx).First(user, userID).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err == nil && user.Phone != nil {
+		phone := *user.Phone
+
+		keys := []string{
+			fmt.Sprintf("code_resend:%s", phone),
+			fmt.Sprintf("code_send_daily:%s", phone),
+		}
+		for _, purpose := range []string{biz.PurposeRegister, biz.PurposeResetPassword, biz.PurposeChangePhone} {
+			keys = append(keys, getVerificationCodeKey(purpose, phone))
+		}
+		for _, kind := range []string{"fail", "lock", "locks", "delay"} {
+			keys = append(keys, loginGuardKey(kind, biz.LockScopePhone, phone))
+		}
+		deleted, err := r.redisClient.Del(ctx, keys...).Result()
+		if err != nil {
+			return nil, err
+		}
+		detail["cache_keys"] = deleted
+
+		redacted := r.pg.WithContext(ctx).Model(&models.AuditLog{}).
+			Where("target = ?", biz.LockScopePhone+":"+phone).
+			Updates(map[string]any{"target": redactedTarget, "ip": ""})
+		if redacted.Error != nil {
+			return nil, redacted.Error
+		}
+		detail["audit_logs_redacted"] = redacted.RowsAffected
+	}
+
+	err = r.pg.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		profiles := tx.Where("user_id = ?", userID).Delete(&models.Profile{})
+		if profiles.Error != nil {
+			return profiles.Error
+		}
+		users := tx.Where("id = ?", userID).Delete(&models.User{})
+		if users.Error != nil {
+			return users.Error
+		}
+
+		detail["profiles"] = profiles.RowsAffected
+		detail["users"] = users.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
@@ -0,0 +1,14 @@
+package data
+
+import (
+	"github.com/Fl0rencess720/Doria/src/common/health"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+func NewHealthChecker(pg *gorm.DB, rdb *redis.Client) *health.Checker {
+	checker := health.NewChecker()
+	checker.Add("postgres", health.Postgres(pg))
+	checker.Add("redis", health.Redis(rdb))
+	return checker
+}
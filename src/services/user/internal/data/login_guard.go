@@ -0,0 +1,151 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Fl0rencess720/Doria/src/services/user/internal/biz"
+	"github.com/redis/go-redis/v9"
+)
+
+// beginLoginScript returns {wait ms, locked} for the first of the account
+// lock, IP lock and account delay keys (KEYS[1..3]) that is set. Otherwise it
+// counts the attempt against the account (KEYS[4]) before the password is checked,
+// arming the delay for the next one, and returns {0, 0}. Concurrent guesses
+// therefore see each other's delay instead of all passing at once.
+var beginLoginScript = redis.NewScript(`
+local window, delayAfter, baseDelay, maxDelay = tonumber(ARGV[1]), tonumber(ARGV[2]), tonumber(ARGV[3]), tonumber(ARGV[4])
+
+for i = 1, 3 do
+  if KEYS[i] ~= "" then
+    local ttl = redis.call("PTTL", KEYS[i])
+    if ttl > 0 then
+      return {ttl, i < 3 and 1 or 0}
+    end
+  end
+end
+
+if KEYS[4] ~= "" then
+  local attempts = redis.call("INCR", KEYS[4])
+  if attempts == 1 then
+    redis.call("PEXPIRE", KEYS[4], window)
+  end
+  if attempts >= delayAfter and KEYS[3] ~= "" then
+    local delay = math.min(baseDelay * 2 ^ (attempts - delayAfter), maxDelay)
+    redis.call("SET", KEYS[3], 1, "PX", math.floor(delay))
+  end
+end
+
+return {0, 0}
+`)
+
+// recordLoginFailureScript locks the account (KEYS[1..4]) once the attempts
+// counted by beginLoginScript reach the threshold, counts a failure for the
+// IP (KEYS[5..7]) and returns the lock durations in ms it set for each, 0
+// when none.
+var recordLoginFailureScript = redis.NewScript(`
+local window = tonumber(ARGV[1])
+local phoneThreshold, ipThreshold = tonumber(ARGV[2]), tonumber(ARGV[3])
+local lockDuration, maxLockDuration = tonumber(ARGV[4]), tonumber(ARGV[5])
+
+local function count(key)
+  local n = redis.call("INCR", key)
+  if n == 1 then
+    redis.call("PEXPIRE", key, window)
+  end
+  return n
+end
+
+local function lock(failKey, lockKey, lockCountKey)
+  local locks = redis.call("INCR", lockCountKey)
+  if locks == 1 then
+    redis.call("PEXPIRE", lockCountKey, 86400000)
+  end
+  local duration = math.min(lockDuration * 2 ^ (locks - 1), maxLockDuration)
+  redis.call("SET", lockKey, 1, "PX", math.floor(duration))
+  redis.call("DEL", failKey)
+  return math.floor(duration)
+end
+
+local phoneLock, ipLock = 0, 0
+
+local phoneFails = tonumber(redis.call("GET", KEYS[1]) or "0")
+if phoneFails >= phoneThreshold then
+  phoneLock = lock(KEYS[1], KEYS[2], KEYS[3])
+  redis.call("DEL", KEYS[4])
+end
+
+if KEYS[5] ~= "" and count(KEYS[5]) >= ipThreshold then
+  ipLock = lock(KEYS[5], KEYS[6], KEYS[7])
+end
+
+return {phoneLock, ipLock}
+`)
+
+type loginGuardRepo struct {
+	redisClient *redis.Client
+}
+
+func NewLoginGuardRepo(rdb *redis.Client) biz.LoginGuardRepo {
+	return &loginGuardRepo{
+		redisClient: rdb,
+	}
+}
+
+func loginGuardKey(kind, scope, subject string) string {
+	if subject == "" {
+		return ""
+	}
+	return fmt.Sprintf("login_%s:%s:%s", kind, scope, subject)
+}
+
+func (r *loginGuardRepo) BeginLogin(ctx context.Context, scope, subject, ip string, policy *biz.LoginGuardPolicy) (time.Duration, bool, error) {
+	result, err := beginLoginScript.Run(ctx, r.redisClient, []string{
+		loginGuardKey("lock", scope, subject),
+		loginGuardKey("lock", biz.LockScopeIP, ip),
+		loginGuardKey("delay", scope, subject),
+		loginGuardKey("fail", scope, subject),
+	},
+		policy.Window.Milliseconds(), policy.DelayAfter, policy.BaseDelay.Milliseconds(), policy.MaxDelay.Milliseconds(),
+	).Int64Slice()
+	if err != nil {
+		return 0, false, err
+	}
+
+	return time.Duration(result[0]) * time.Millisecond, result[1] == 1, nil
+}
+
+func (r *loginGuardRepo) RecordLoginFailure(ctx context.Context, scope, subject, ip string, policy *biz.LoginGuardPolicy) (map[string]time.Duration, error) {
+	result, err := recordLoginFailureScript.Run(ctx, r.redisClient, []string{
+		loginGuardKey("fail", scope, subject),
+		loginGuardKey("lock", scope, subject),
+		loginGuardKey("locks", scope, subject),
+		loginGuardKey("delay", scope, subject),
+		loginGuardKey("fail", biz.LockScopeIP, ip),
+		loginGuardKey("lock", biz.LockScopeIP, ip),
+		loginGuardKey("locks", biz.LockScopeIP, ip),
+	},
+		policy.Window.Milliseconds(), policy.PhoneLockThreshold, policy.IPLockThreshold,
+		policy.LockDuration.Milliseconds(), policy.MaxLockDuration.Milliseconds(),
+	).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	lockouts := map[string]time.Duration{}
+	if result[0] > 0 {
+		lockouts[scope] = time.Duration(result[0]) * time.Millisecond
+	}
+	if result[1] > 0 {
+		lockouts[biz.LockScopeIP] = time.Duration(result[1]) * time.Millisecond
+	}
+	return lockouts, nil
+}
+
+func (r *loginGuardRepo) ResetLoginFailures(ctx context.Context, scope, subject string) error {
+	return r.redisClient.Del(ctx,
+		loginGuardKey("fail", scope, subject),
+		loginGuardKey("delay", scope, subject),
+	).Err()
+}
@@ -0,0 +1,28 @@
+package data
+
+import (
+	"context"
+
+	"github.com/Fl0rencess720/Doria/src/common/registry"
+	mateapi "github.com/Fl0rencess720/Doria/src/rpc/mate"
+	_ "github.com/mbobakov/grpc-consul-resolver"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func NewMateClient() mateapi.MateServiceClient {
+	discoveryManager := registry.NewDiscoveryManager()
+
+	conn, err := discoveryManager.CreateGrpcConnection(
+		context.Background(),
+		"doria-mate",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		zap.L().Panic("new grpc client failed", zap.Error(err))
+	}
+
+	client := mateapi.NewMateServiceClient(conn)
+	return client
+}
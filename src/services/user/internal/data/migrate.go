@@ -0,0 +1,19 @@
+package data
+
+import (
+	"github.com/Fl0rencess720/Doria/src/services/user/internal/models"
+	"gorm.io/gorm"
+)
+
+// migrate brings the tables the user service owns up to date with its
+// models. AutoMigrate only adds tables, columns, indexes and constraints, so
+// it is safe to run on every start.
+func migrate(db *gorm.DB) error {
+	return db.AutoMigrate(
+		&models.User{},
+		&models.AuditLog{},
+		&models.Profile{},
+		&models.AccountDeletion{},
+		&models.AccountDeletionStep{},
+	)
+}
@@ -0,0 +1,31 @@
+package data
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Fl0rencess720/Doria/src/services/user/internal/biz"
+	"github.com/Fl0rencess720/Doria/src/services/user/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (u *userRepo) GetProfile(ctx context.Context, userID uint) (*models.Profile, error) {
+	profile := &models.Profile{}
+
+	if err := u.pg.WithContext(ctx).Where("user_id = ?", userID).First(profile).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, biz.ErrProfileNotFound
+		}
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+func (u *userRepo) SaveProfile(ctx context.Context, profile *models.Profile) error {
+	return u.pg.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		UpdateAll: true,
+	}).Create(profile).Error
+}
@@ -0,0 +1,62 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Fl0rencess720/Doria/src/services/user/internal/biz"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// NewSMSSender picks the sender named by sms.provider. Only development
+// stubs exist so far; a real gateway plugs in here as another provider.
+func NewSMSSender() biz.SMSSender {
+	switch provider := viper.GetString("sms.provider"); provider {
+	case "", "log":
+		if viper.GetString("project.mode") != "dev" {
+			zap.L().Warn("verification codes are only written to the log, set sms.provider for real delivery")
+		}
+		return &logSMSSender{}
+	case "file":
+		path := viper.GetString("sms.file_path")
+		if path == "" {
+			zap.L().Panic("sms.file_path is required for the file sms provider")
+		}
+		return &fileSMSSender{path: path}
+	default:
+		zap.L().Panic("unknown sms provider", zap.String("provider", provider))
+		return nil
+	}
+}
+
+type logSMSSender struct{}
+
+func (s *logSMSSender) Send(ctx context.Context, phone, content string) error {
+	zap.L().Info("sms sent", zap.String("phone", phone), zap.String("content", content))
+	return nil
+}
+
+// fileSMSSender appends every message to a file, one per line, so local
+// tooling and manual testing can pick up codes.
+type fileSMSSender struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (s *fileSMSSender) Send(ctx context.Context, phone, content string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "%s\t%s\t%s\n", time.Now().Format(time.RFC3339), phone, content)
+	return err
+}
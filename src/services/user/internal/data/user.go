@@ -2,6 +2,7 @@ package data
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/Fl0rencess720/Doria/src/services/user/internal/biz"
@@ -33,6 +34,9 @@ func NewPostgres() *gorm.DB {
 	if err != nil {
 		zap.L().Panic("failed to connect to postgres", zap.Error(err))
 	}
+	if err := migrate(db); err != nil {
+		zap.L().Panic("failed to migrate postgres", zap.Error(err))
+	}
 	return db
 }
 
@@ -73,32 +77,28 @@ func (u *userRepo) GetUser(ctx context.Context, userID uint) (*models.User, erro
 	user := &models.User{}
 
 	if err := u.pg.WithContext(ctx).First(user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, biz.ErrUserNotFound
+		}
 		return nil, err
 	}
 
 	return user, nil
 }
 
-func (u *userRepo) VerifyRegisterCode(ctx context.Context, phone string, code string) (bool, error) {
-	redisKey := fmt.Sprintf("register_code:%s", phone)
-	redisCode, err := u.redisClient.Get(ctx, redisKey).Result()
-	if err != nil {
-		return false, err
-	}
+func (u *userRepo) GetUserByPhone(ctx context.Context, phone string) (*models.User, error) {
+	user := &models.User{}
 
-	if redisCode != code {
-		return false, nil
+	if err := u.pg.WithContext(ctx).Where("phone = ?", phone).First(user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, biz.ErrUserNotFound
+		}
+		return nil, err
 	}
 
-	return true, nil
+	return user, nil
 }
 
-func (u *userRepo) VerifyUserPassword(ctx context.Context, phone string, password string) (bool, uint, error) {
-	user := &models.User{}
-
-	if err := u.pg.WithContext(ctx).Where("phone = ?", phone).Where("password = ?", password).First(user).Error; err != nil {
-		return false, 0, err
-	}
-
-	return true, user.ID, nil
+func (u *userRepo) UpdatePassword(ctx context.Context, userID uint, passwordHash string) error {
+	return u.pg.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).Update("password", passwordHash).Error
 }
@@ -8,7 +8,56 @@ type User struct {
 	ID        uint      `gorm:"primaryKey"`
 	Username  string    `gorm:"type:text;unique;not null"`
 	Status    string    `gorm:"type:text;not null;check:status IN ('user','visitor')"`
+	Role      string    `gorm:"type:text;not null;default:'user';check:role IN ('user','support','admin')"`
+	Disabled  bool      `gorm:"not null;default:false"`
 	Phone     *string   `gorm:"type:text;unique"`
 	Password  string    `gorm:"type:text;not null"`
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 }
+
+type Profile struct {
+	UserID    uint      `gorm:"primaryKey"`
+	Nickname  string    `gorm:"type:text;not null;default:''"`
+	AvatarURL string    `gorm:"type:text;not null;default:''"`
+	Timezone  string    `gorm:"type:text;not null;default:''"`
+	Locale    string    `gorm:"type:text;not null;default:''"`
+	TTSVoice  string    `gorm:"column:tts_voice;type:text;not null;default:''"`
+	TTSSpeed  int       `gorm:"column:tts_speed;not null;default:50;check:tts_speed BETWEEN 0 AND 100"`
+	AddressAs string    `gorm:"type:text;not null;default:''"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+type AuditLog struct {
+	ID        uint      `gorm:"primaryKey"`
+	ActorID   uint      `gorm:"index;not null"`
+	Action    string    `gorm:"type:text;not null"`
+	Target    string    `gorm:"type:text"`
+	Status    int       `gorm:"not null"`
+	IP        string    `gorm:"type:text"`
+	Detail    string    `gorm:"type:text"`
+	CreatedAt time.Time `gorm:"autoCreateTime;index"`
+}
+
+// AccountDeletion tracks the erasure of one account across services. Steps
+// are recorded as they finish so a crashed worker resumes where it stopped.
+type AccountDeletion struct {
+	ID            string    `gorm:"type:uuid;primaryKey"`
+	UserID        uint      `gorm:"uniqueIndex;not null"`
+	Status        string    `gorm:"type:text;not null;check:status IN ('pending','completed')"`
+	TokenHash     string    `gorm:"type:text;not null"`
+	Attempts      int       `gorm:"not null;default:0"`
+	LastError     string    `gorm:"type:text;not null;default:''"`
+	NextAttemptAt time.Time `gorm:"not null;index"`
+	RequestedAt   time.Time `gorm:"autoCreateTime"`
+	CompletedAt   *time.Time
+	Steps         []*AccountDeletionStep `gorm:"foreignKey:DeletionID"`
+}
+
+type AccountDeletionStep struct {
+	DeletionID  string `gorm:"type:uuid;primaryKey"`
+	Name        string `gorm:"type:text;primaryKey"`
+	Position    int    `gorm:"not null"`
+	Status      string `gorm:"type:text;not null;check:status IN ('pending','completed')"`
+	Detail      string `gorm:"type:text;not null;default:''"`
+	CompletedAt *time.Time
+}
@@ -0,0 +1,152 @@
+package password
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/wire"
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/argon2"
+)
+
+var ProviderSet = wire.NewSet(NewHasher)
+
+var ErrInvalidHash = errors.New("invalid password hash")
+
+// Params are the argon2id cost settings. Changing them only affects new
+// hashes; existing ones are upgraded the next time their owner logs in.
+type Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// Hasher stores passwords as argon2id hashes in the PHC string format
+// ($argon2id$v=19$m=...,t=...,p=...$salt$key) and still accepts the unsalted
+// MD5 hex digests written before, flagging them for rehashing.
+type Hasher struct {
+	params Params
+
+	dummyOnce sync.Once
+	dummyHash string
+}
+
+func NewHasher() *Hasher {
+	params := Params{
+		Memory:      64 * 1024,
+		Iterations:  3,
+		Parallelism: 2,
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+	if v := viper.GetUint32("password.argon2.memory_kib"); v > 0 {
+		params.Memory = v
+	}
+	if v := viper.GetUint32("password.argon2.iterations"); v > 0 {
+		params.Iterations = v
+	}
+	if v := viper.GetUint8("password.argon2.parallelism"); v > 0 {
+		params.Parallelism = v
+	}
+	if v := viper.GetUint32("password.argon2.salt_length"); v > 0 {
+		params.SaltLength = v
+	}
+	if v := viper.GetUint32("password.argon2.key_length"); v > 0 {
+		params.KeyLength = v
+	}
+
+	return &Hasher{params: params}
+}
+
+func (h *Hasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.params.Memory, h.params.Iterations, h.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify reports whether password matches encoded. needsRehash is set when
+// the match used a legacy MD5 digest or argon2id parameters other than the
+// configured ones.
+func (h *Hasher) Verify(password, encoded string) (ok, needsRehash bool, err error) {
+	if isLegacyMD5(encoded) {
+		sum := md5.Sum([]byte(password))
+		ok = subtle.ConstantTimeCompare([]byte(hex.EncodeToString(sum[:])), []byte(strings.ToLower(encoded))) == 1
+		return ok, ok, nil
+	}
+
+	params, salt, key, err := decode(encoded)
+	if err != nil {
+		return false, false, err
+	}
+
+	other := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return false, false, nil
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return true, params != h.params, nil
+}
+
+// VerifyDummy spends the time of a real Verify without a hash to check, so a
+// caller that found no account doesn't reveal that through its timing.
+func (h *Hasher) VerifyDummy(password string) {
+	h.dummyOnce.Do(func() {
+		h.dummyHash, _ = h.Hash("doria-dummy-password")
+	})
+	h.Verify(password, h.dummyHash)
+}
+
+func decode(encoded string) (Params, []byte, []byte, error) {
+	var params Params
+
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrInvalidHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, ErrInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrInvalidHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrInvalidHash
+	}
+
+	return params, salt, key, nil
+}
+
+func isLegacyMD5(encoded string) bool {
+	if len(encoded) != md5.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(encoded)
+	return err == nil
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+
+	userapi "github.com/Fl0rencess720/Doria/src/rpc/user"
+	"github.com/Fl0rencess720/Doria/src/services/user/internal/models"
+)
+
+func (s *UserService) GetUserAccess(ctx context.Context, req *userapi.GetUserAccessRequest) (*userapi.GetUserAccessResponse, error) {
+	access, err := s.userUseCase.GetUserAccess(ctx, uint(req.UserId))
+	if err != nil {
+		return nil, err
+	}
+
+	return &userapi.GetUserAccessResponse{
+		Role:        access.Role,
+		Permissions: access.Permissions,
+		Disabled:    access.Disabled,
+	}, nil
+}
+
+func (s *UserService) ListUsers(ctx context.Context, req *userapi.ListUsersRequest) (*userapi.ListUsersResponse, error) {
+	users, total, err := s.userUseCase.ListUsers(ctx, req.Query, int(req.Page), int(req.PageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &userapi.ListUsersResponse{
+		Users: make([]*userapi.UserInfo, len(users)),
+		Total: total,
+	}
+	for i, user := range users {
+		info := &userapi.UserInfo{
+			Id:        int32(user.ID),
+			Username:  user.Username,
+			Status:    user.Status,
+			Role:      user.Role,
+			Disabled:  user.Disabled,
+			CreatedAt: user.CreatedAt.Unix(),
+		}
+		if user.Phone != nil {
+			info.Phone = *user.Phone
+		}
+		resp.Users[i] = info
+	}
+
+	return resp, nil
+}
+
+func (s *UserService) SetUserDisabled(ctx context.Context, req *userapi.SetUserDisabledRequest) (*userapi.SetUserDisabledResponse, error) {
+	if err := s.userUseCase.SetUserDisabled(ctx, uint(req.UserId), req.Disabled); err != nil {
+		return nil, err
+	}
+	return &userapi.SetUserDisabledResponse{}, nil
+}
+
+func (s *UserService) SetUserRole(ctx context.Context, req *userapi.SetUserRoleRequest) (*userapi.SetUserRoleResponse, error) {
+	if err := s.userUseCase.SetUserRole(ctx, uint(req.UserId), req.Role); err != nil {
+		return nil, err
+	}
+	return &userapi.SetUserRoleResponse{}, nil
+}
+
+func (s *UserService) RecordAudit(ctx context.Context, req *userapi.RecordAuditRequest) (*userapi.RecordAuditResponse, error) {
+	log := req.GetLog()
+	if err := s.userUseCase.RecordAudit(ctx, &models.AuditLog{
+		ActorID: uint(log.GetActorId()),
+		Action:  log.GetAction(),
+		Target:  log.GetTarget(),
+		Status:  int(log.GetStatus()),
+		IP:      log.GetIp(),
+		Detail:  log.GetDetail(),
+	}); err != nil {
+		return nil, err
+	}
+	return &userapi.RecordAuditResponse{}, nil
+}
+
+func (s *UserService) ListAuditLogs(ctx context.Context, req *userapi.ListAuditLogsRequest) (*userapi.ListAuditLogsResponse, error) {
+	logs, total, err := s.userUseCase.ListAuditLogs(ctx, uint(req.ActorId), int(req.Page), int(req.PageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &userapi.ListAuditLogsResponse{
+		Logs:  make([]*userapi.AuditLog, len(logs)),
+		Total: total,
+	}
+	for i, log := range logs {
+		resp.Logs[i] = &userapi.AuditLog{
+			Id:        int64(log.ID),
+			ActorId:   int32(log.ActorID),
+			Action:    log.Action,
+			Target:    log.Target,
+			Status:    int32(log.Status),
+			Ip:        log.IP,
+			Detail:    log.Detail,
+			CreatedAt: log.CreatedAt.Unix(),
+		}
+	}
+
+	return resp, nil
+}
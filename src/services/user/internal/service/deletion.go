@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+
+	userapi "github.com/Fl0rencess720/Doria/src/rpc/user"
+	"github.com/Fl0rencess720/Doria/src/services/user/internal/models"
+)
+
+func (s *UserService) DeleteAccount(ctx context.Context, req *userapi.DeleteAccountRequest) (*userapi.DeleteAccountResponse, error) {
+	deletionID, token, err := s.deletionUseCase.DeleteAccount(ctx, uint(req.UserId), req.Password)
+	if err != nil {
+		return nil, err
+	}
+	return &userapi.DeleteAccountResponse{DeletionId: deletionID, Token: token}, nil
+}
+
+func (s *UserService) GetAccountDeletion(ctx context.Context, req *userapi.GetAccountDeletionRequest) (*userapi.GetAccountDeletionResponse, error) {
+	deletion, err := s.deletionUseCase.GetAccountDeletion(ctx, req.DeletionId, req.Token)
+	if err != nil {
+		return nil, err
+	}
+	return &userapi.GetAccountDeletionResponse{Deletion: toAccountDeletion(deletion)}, nil
+}
+
+func toAccountDeletion(deletion *models.AccountDeletion) *userapi.AccountDeletion {
+	resp := &userapi.AccountDeletion{
+		Id:          deletion.ID,
+		UserId:      int32(deletion.UserID),
+		Status:      deletion.Status,
+		RequestedAt: deletion.RequestedAt.Unix(),
+		Attempts:    int32(deletion.Attempts),
+		Steps:       make([]*userapi.DeletionStep, len(deletion.Steps)),
+	}
+	if deletion.CompletedAt != nil {
+		resp.CompletedAt = deletion.CompletedAt.Unix()
+	}
+	for i, step := range deletion.Steps {
+		resp.Steps[i] = &userapi.DeletionStep{
+			Name:   step.Name,
+			Status: step.Status,
+			Detail: step.Detail,
+		}
+		if step.CompletedAt != nil {
+			resp.Steps[i].CompletedAt = step.CompletedAt.Unix()
+		}
+	}
+	return resp
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+
+	userapi "github.com/Fl0rencess720/Doria/src/rpc/user"
+	"github.com/Fl0rencess720/Doria/src/services/user/internal/biz"
+	"github.com/Fl0rencess720/Doria/src/services/user/internal/models"
+)
+
+func (s *UserService) GetProfile(ctx context.Context, req *userapi.GetProfileRequest) (*userapi.GetProfileResponse, error) {
+	profile, err := s.userUseCase.GetProfile(ctx, uint(req.UserId))
+	if err != nil {
+		return nil, err
+	}
+	return &userapi.GetProfileResponse{Profile: toProfile(profile)}, nil
+}
+
+func (s *UserService) UpdateProfile(ctx context.Context, req *userapi.UpdateProfileRequest) (*userapi.UpdateProfileResponse, error) {
+	update := &biz.UpdateProfileReq{
+		UserID:    uint(req.UserId),
+		Nickname:  req.Nickname,
+		AvatarURL: req.AvatarUrl,
+		Timezone:  req.Timezone,
+		Locale:    req.Locale,
+		TTSVoice:  req.TtsVoice,
+		AddressAs: req.AddressAs,
+	}
+	if req.TtsSpeed != nil {
+		speed := int(*req.TtsSpeed)
+		update.TTSSpeed = &speed
+	}
+
+	profile, err := s.userUseCase.UpdateProfile(ctx, update)
+	if err != nil {
+		return nil, err
+	}
+	return &userapi.UpdateProfileResponse{Profile: toProfile(profile)}, nil
+}
+
+func toProfile(profile *models.Profile) *userapi.Profile {
+	resp := &userapi.Profile{
+		Nickname:  profile.Nickname,
+		AvatarUrl: profile.AvatarURL,
+		Timezone:  profile.Timezone,
+		Locale:    profile.Locale,
+		TtsVoice:  profile.TTSVoice,
+		TtsSpeed:  int32(profile.TTSSpeed),
+		AddressAs: profile.AddressAs,
+	}
+	if !profile.UpdatedAt.IsZero() {
+		resp.UpdatedAt = profile.UpdatedAt.Unix()
+	}
+	return resp
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
@@ -8,7 +9,10 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/Fl0rencess720/Doria/src/common/health"
+	"github.com/Fl0rencess720/Doria/src/common/metrics"
 	"github.com/Fl0rencess720/Doria/src/common/registry"
+	"github.com/Fl0rencess720/Doria/src/common/rpcerr"
 	userapi "github.com/Fl0rencess720/Doria/src/rpc/user"
 	"github.com/Fl0rencess720/Doria/src/services/user/internal/biz"
 	"github.com/google/wire"
@@ -28,11 +32,14 @@ type UserService struct {
 	registry    *registry.RegistrationManager
 	server      *grpc.Server
 	listener    net.Listener
+	health      *health.Checker
+	cancel      context.CancelFunc
 
-	userUseCase *biz.UserUseCase
+	userUseCase     *biz.UserUseCase
+	deletionUseCase *biz.DeletionUseCase
 }
 
-func NewUserService(serviceName string, userUseCase *biz.UserUseCase) *UserService {
+func NewUserService(serviceName string, userUseCase *biz.UserUseCase, deletionUseCase *biz.DeletionUseCase, checker *health.Checker) *UserService {
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", viper.GetInt("server.grpc.port")))
 	if err != nil {
 		panic(err)
@@ -51,20 +58,25 @@ func NewUserService(serviceName string, userUseCase *biz.UserUseCase) *UserServi
 	server := grpc.NewServer(
 		grpc.KeepaliveEnforcementPolicy(kaep),
 		grpc.KeepaliveParams(kasp),
+		grpc.ChainUnaryInterceptor(metrics.UnaryServerInterceptor(), rpcerr.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(metrics.StreamServerInterceptor(), rpcerr.StreamServerInterceptor()),
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 	)
 
 	registrationManager := registry.NewRegistrationManager()
 
 	s := &UserService{
-		serviceName: serviceName,
-		registry:    registrationManager,
-		server:      server,
-		listener:    lis,
-		userUseCase: userUseCase,
+		serviceName:     serviceName,
+		registry:        registrationManager,
+		server:          server,
+		listener:        lis,
+		health:          checker,
+		userUseCase:     userUseCase,
+		deletionUseCase: deletionUseCase,
 	}
 
 	userapi.RegisterUserServiceServer(server, s)
+	checker.Register(server)
 
 	return s
 }
@@ -76,7 +88,12 @@ func (s *UserService) Start() error {
 	}
 	s.serviceID = serviceID
 
-	go s.registry.SetTTLHealthCheck()
+	s.health.Start(context.Background())
+	go s.registry.SetTTLHealthCheck(s.health.Err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.deletionUseCase.Start(ctx)
 
 	go func() {
 		if err := s.server.Serve(s.listener); err != nil {
@@ -94,6 +111,10 @@ func (s *UserService) Stop() error {
 				zap.Error(err))
 		}
 	}
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.health.Shutdown()
 	zap.L().Info("Shutting down gRPC server...")
 	s.server.GracefulStop()
 	return nil
@@ -2,13 +2,14 @@ package service
 
 import (
 	"context"
+	"time"
 
 	userapi "github.com/Fl0rencess720/Doria/src/rpc/user"
 	"github.com/Fl0rencess720/Doria/src/services/user/internal/biz"
 )
 
 func (s *UserService) Register(ctx context.Context, req *userapi.RegisterRequest) (*userapi.RegisterResponse, error) {
-	userID, code, err := s.userUseCase.Register(ctx, &biz.UserRegisterReq{
+	userID, err := s.userUseCase.Register(ctx, &biz.UserRegisterReq{
 		Phone:    req.Phone,
 		Code:     req.Code,
 		Password: req.Password,
@@ -19,20 +20,105 @@ func (s *UserService) Register(ctx context.Context, req *userapi.RegisterRequest
 
 	return &userapi.RegisterResponse{
 		UserId: int32(userID),
-		Code:   int32(code),
 	}, nil
 }
 
 func (s *UserService) Login(ctx context.Context, req *userapi.LoginRequest) (*userapi.LoginResponse, error) {
-	userID, code, err := s.userUseCase.Login(ctx, &biz.UserLoginReq{
+	userID, err := s.userUseCase.Login(ctx, &biz.UserLoginReq{
 		Phone:    req.Phone,
 		Password: req.Password,
+		ClientIP: req.ClientIp,
 	})
 	if err != nil {
 		return nil, err
 	}
 	return &userapi.LoginResponse{
 		UserId: int32(userID),
-		Code:   int32(code),
 	}, nil
 }
+
+func (s *UserService) SendVerificationCode(ctx context.Context, req *userapi.SendVerificationCodeRequest) (*userapi.SendVerificationCodeResponse, error) {
+	resp, err := s.userUseCase.SendVerificationCode(ctx, &biz.SendCodeReq{
+		Phone:    req.Phone,
+		Purpose:  req.Purpose,
+		ClientIP: req.ClientIp,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &userapi.SendVerificationCodeResponse{
+		ExpiresIn:   int64(resp.ExpiresIn / time.Second),
+		ResendAfter: int64(resp.ResendAfter / time.Second),
+	}, nil
+}
+
+func (s *UserService) ChangePhone(ctx context.Context, req *userapi.ChangePhoneRequest) (*userapi.ChangePhoneResponse, error) {
+	if err := s.userUseCase.ChangePhone(ctx, &biz.ChangePhoneReq{
+		UserID:          uint(req.UserId),
+		NewPhone:        req.NewPhone,
+		Code:            req.Code,
+		CurrentPassword: req.CurrentPassword,
+	}); err != nil {
+		return nil, err
+	}
+	return &userapi.ChangePhoneResponse{}, nil
+}
+
+func (s *UserService) RequestPasswordReset(ctx context.Context, req *userapi.RequestPasswordResetRequest) (*userapi.RequestPasswordResetResponse, error) {
+	resp, err := s.userUseCase.RequestPasswordReset(ctx, req.Phone, req.ClientIp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userapi.RequestPasswordResetResponse{
+		ExpiresIn:   int64(resp.ExpiresIn / time.Second),
+		ResendAfter: int64(resp.ResendAfter / time.Second),
+	}, nil
+}
+
+func (s *UserService) ResetPassword(ctx context.Context, req *userapi.ResetPasswordRequest) (*userapi.ResetPasswordResponse, error) {
+	userID, err := s.userUseCase.ResetPassword(ctx, &biz.ResetPasswordReq{
+		Phone:       req.Phone,
+		Code:        req.Code,
+		NewPassword: req.NewPassword,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &userapi.ResetPasswordResponse{
+		UserId: int32(userID),
+	}, nil
+}
+
+func (s *UserService) ChangePassword(ctx context.Context, req *userapi.ChangePasswordRequest) (*userapi.ChangePasswordResponse, error) {
+	if err := s.userUseCase.ChangePassword(ctx, &biz.ChangePasswordReq{
+		UserID:          uint(req.UserId),
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
+	}); err != nil {
+		return nil, err
+	}
+	return &userapi.ChangePasswordResponse{}, nil
+}
+
+func (s *UserService) CreateVisitor(ctx context.Context, req *userapi.CreateVisitorRequest) (*userapi.CreateVisitorResponse, error) {
+	userID, err := s.userUseCase.CreateVisitor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &userapi.CreateVisitorResponse{UserId: int32(userID)}, nil
+}
+
+func (s *UserService) UpgradeVisitor(ctx context.Context, req *userapi.UpgradeVisitorRequest) (*userapi.UpgradeVisitorResponse, error) {
+	if err := s.userUseCase.UpgradeVisitor(ctx, &biz.UpgradeVisitorReq{
+		UserID:   uint(req.UserId),
+		Phone:    req.Phone,
+		Code:     req.Code,
+		Password: req.Password,
+	}); err != nil {
+		return nil, err
+	}
+	return &userapi.UpgradeVisitorResponse{}, nil
+}